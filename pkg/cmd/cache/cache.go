@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"github.com/rafaelcalleja/go-bpkg/pkg/repository"
+	cmdHelper "github.com/rafaelcalleja/go-kit/cmd/helper"
+	"github.com/rafaelcalleja/go-kit/cmd/termcolor"
+	"github.com/rafaelcalleja/go-kit/logger"
+	"github.com/spf13/cobra"
+)
+
+type BaseOptions struct {
+	cacheDir string
+}
+
+func NewCmdCache(
+	helper cmdHelper.ErrorHelper,
+	log logger.Logger,
+	term termcolor.TermColor,
+) *cobra.Command {
+	o := &BaseOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "cache <command>",
+		Short: "List, clean and prune the download cache",
+		Long:  `Manage the downloaded release files reused by install, upgrade and info, stored at $GO_BPKG_CACHE or the user cache dir.`,
+	}
+
+	cmd.PersistentFlags().StringVar(&o.cacheDir, "cache-dir", "", "download cache dir, $GO_BPKG_CACHE or go-bpkg in the user cache dir by default")
+
+	cmd.AddCommand(NewCmdLs(o, helper, log, term))
+	cmd.AddCommand(NewCmdClean(o, helper, log, term))
+	cmd.AddCommand(NewCmdPrune(o, helper, log, term))
+
+	return cmd
+}
+
+func (o *BaseOptions) downloadCache() *repository.DownloadCache {
+	if "" == o.cacheDir {
+		o.cacheDir = repository.DefaultCachePath()
+	}
+
+	return repository.NewDownloadCache(o.cacheDir)
+}
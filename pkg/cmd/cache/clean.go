@@ -0,0 +1,26 @@
+package cache
+
+import (
+	cmdHelper "github.com/rafaelcalleja/go-kit/cmd/helper"
+	"github.com/rafaelcalleja/go-kit/cmd/termcolor"
+	"github.com/rafaelcalleja/go-kit/logger"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdClean(
+	o *BaseOptions,
+	helper cmdHelper.ErrorHelper,
+	log logger.Logger,
+	term termcolor.TermColor,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clean",
+		Short: "Remove every cached download",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			helper.CheckErr(o.downloadCache().Clean())
+
+			log.Infof("Download cache %s cleaned", term.ColorInfo(o.cacheDir))
+		},
+	}
+}
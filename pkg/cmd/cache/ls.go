@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	cmdHelper "github.com/rafaelcalleja/go-kit/cmd/helper"
+	"github.com/rafaelcalleja/go-kit/cmd/termcolor"
+	"github.com/rafaelcalleja/go-kit/logger"
+	"github.com/spf13/cobra"
+	"text/tabwriter"
+	"time"
+)
+
+func NewCmdLs(
+	o *BaseOptions,
+	helper cmdHelper.ErrorHelper,
+	log logger.Logger,
+	term termcolor.TermColor,
+) *cobra.Command {
+	output := "table"
+
+	newCmd := &cobra.Command{
+		Use:   "ls",
+		Short: "List the cached downloads",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if "table" != output && "json" != output {
+				helper.CheckErr(errors.New(fmt.Sprintf("unknown output format %s, use table or json", output)))
+			}
+
+			entries, err := o.downloadCache().List()
+			helper.CheckErr(err)
+
+			if "json" == output {
+				content, err := json.MarshalIndent(entries, "", "  ")
+				helper.CheckErr(err)
+
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(content))
+
+				return
+			}
+
+			if 0 == len(entries) {
+				log.Infof("No downloads cached at %s", term.ColorInfo(o.cacheDir))
+
+				return
+			}
+
+			writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintln(writer, "PACKAGE\tFILE\tSIZE\tLAST USED")
+			for _, entry := range entries {
+				_, _ = fmt.Fprintf(writer, "%s\t%s\t%d\t%s\n", entry.Key(), entry.File, entry.Size,
+					entry.LastUsedAt.Local().Format(time.RFC3339))
+			}
+
+			helper.CheckErr(writer.Flush())
+		},
+	}
+
+	newCmd.Flags().StringVarP(&output, "output", "o", "table", "output format: table or json")
+
+	return newCmd
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	cmdHelper "github.com/rafaelcalleja/go-kit/cmd/helper"
+	"github.com/rafaelcalleja/go-kit/cmd/termcolor"
+	"github.com/rafaelcalleja/go-kit/logger"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func NewCmdPrune(
+	o *BaseOptions,
+	helper cmdHelper.ErrorHelper,
+	log logger.Logger,
+	term termcolor.TermColor,
+) *cobra.Command {
+	olderThan := ""
+
+	newCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove the cached downloads not used for a while",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			age, err := parseAge(olderThan)
+			helper.CheckErr(err)
+
+			pruned, err := o.downloadCache().Prune(time.Now().Add(-age))
+			helper.CheckErr(err)
+
+			for _, entry := range pruned {
+				log.Infof("Removed %s", term.ColorInfo(entry.Key()))
+			}
+
+			log.Infof("Pruned %d cached downloads", len(pruned))
+		},
+	}
+
+	newCmd.Flags().StringVar(&olderThan, "older-than", "", "remove downloads last used longer ago than this, e.g. 36h or 30d")
+
+	_ = newCmd.MarkFlagRequired("older-than")
+
+	return newCmd
+}
+
+// parseAge accepts go durations and whole days such as 30d
+func parseAge(age string) (time.Duration, error) {
+	if days := strings.TrimSuffix(age, "d"); days != age {
+		if count, err := strconv.Atoi(days); err == nil && count >= 0 {
+			return time.Duration(count) * 24 * time.Hour, nil
+		}
+	}
+
+	duration, err := time.ParseDuration(age)
+	if err != nil || duration < 0 {
+		return 0, errors.New(fmt.Sprintf("invalid --older-than %s, use a duration such as 36h or 30d", age))
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/rafaelcalleja/go-bpkg/pkg/repository"
+	"github.com/rafaelcalleja/go-kit/cmd/helper"
+	"github.com/rafaelcalleja/go-kit/cmd/termcolor"
+	"github.com/rafaelcalleja/go-kit/logger"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func NewPackageInfo(
+	factory *cmdutil.Factory,
+	helper helper.ErrorHelper,
+	log logger.Logger,
+	term termcolor.TermColor,
+) *cobra.Command {
+	o := &PackageInstallOptions{}
+
+	newCmd := &cobra.Command{
+		Use:   "info package/name[:version]",
+		Short: "BPKG describe a remote package without installing it",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if "" != strings.TrimSpace(o.token) {
+				_ = os.Setenv("GITHUB_TOKEN", o.token)
+			}
+
+			var err error
+			o.config, err = repository.NewConfigFromFileName(repository.DefaultConfigPath())
+			helper.CheckErr(err)
+
+			fqpVO, err := repository.NewFullyQualifyPackage(args[0])
+			helper.CheckErr(err)
+
+			scheme, providerOptions := o.providerOptions(factory, fqpVO, o.assetPattern(fqpVO))
+
+			provider, finder, err := o.releaseProviders(scheme, providerOptions)
+			helper.CheckErr(err)
+
+			releaseVersion, err := repository.ResolveReleaseVersion(fqpVO, finder)
+			helper.CheckErr(err)
+
+			log.Infof("Downloading Package %s", term.ColorInfo(releaseVersion.String()))
+
+			asset, err := releaseVersion.DownloadCachedAsset(provider, o.installPath, downloadCache(o.noCache))
+			helper.CheckErr(err)
+
+			// CheckErr exits without running deferred calls, the manifest is all that is needed from the asset
+			metadata, err := releaseVersion.GetPackageMetadata(asset.DecompressPath())
+			_ = asset.Remove()
+			helper.CheckErr(err)
+
+			providerName, hostname := repository.DescribeProvider(provider)
+			installName := repository.PackageInstallName(
+				repository.PackageOrganization(providerName, hostname, releaseVersion.Organization), releaseVersion.Name)
+
+			writePackageInfo(cmd.OutOrStdout(), releaseVersion, metadata, filepath.Join(o.installPath, installName), o.installPath)
+		},
+	}
+
+	newCmd.Flags().StringVar(&o.installPath, "installPath", "./deps", "[package install path] used to show where files would be installed")
+	newCmd.Flags().StringVar(&o.token, "token", "", "Github Token")
+	newCmd.Flags().BoolVar(&o.noCache, "no-cache", false, "download the package again instead of reusing the download cache")
+	newCmd.Flags().StringVar(&o.archive, "archive", repository.ArchiveFormatTarGz, "source archive format to download: tar.gz or zip")
+	newCmd.Flags().StringVar(&o.asset, "asset", "", "describe the release asset matching this pattern instead of the source archive, {{.OS}} and {{.Arch}} are replaced, e.g. mytool_{{.OS}}_{{.Arch}}.tar.gz")
+	newCmd.Flags().StringVar(&o.urlTemplate, "url-template", "", "download the package from an http server, e.g. https://artifacts.corp/{{.Organization}}/{{.Name}}/{{.Version}}.tar.gz")
+	newCmd.Flags().StringVar(&o.indexURL, "index-url", "", "JSON index listing the versions served by --url-template")
+	newCmd.Flags().StringVar(&o.provider, "provider", "", fmt.Sprintf("releases provider of the package when its spec has no scheme: %s, github by default", strings.Join(repository.RegisteredProviders(), ", ")))
+	newCmd.Flags().StringVar(&o.hostname, "hostname", "", "self-managed host of --provider, e.g. ghe.corp.example or gitlab.corp.example, required by gitea, a host in the spec wins")
+	newCmd.Flags().StringVar(&o.remote, "remote", "", "git remote for --provider git, the dir or file:// url of --provider mirror or the base url of --provider registry")
+
+	return newCmd
+}
+
+// writePackageInfo describes the package as installed at destDir of installPath
+func writePackageInfo(out io.Writer, releaseVersion repository.ReleaseVersion, metadata *repository.PackageInstaller, destDir string, installPath string) {
+	_, _ = fmt.Fprintf(out, "Package:     %s\n", releaseVersion.String())
+	_, _ = fmt.Fprintf(out, "Name:        %s\n", metadata.Name)
+	_, _ = fmt.Fprintf(out, "Version:     %s\n", metadata.Version)
+	_, _ = fmt.Fprintf(out, "Description: %s\n", metadata.Description)
+	_, _ = fmt.Fprintf(out, "Directory:   %s\n", destDir)
+
+	writeInfoList(out, "Files", prefixPaths(destDir, metadata.Files))
+	writeInfoList(out, "Scripts", prefixPaths(destDir, metadata.Scripts))
+
+	links := make([]string, 0)
+	for _, script := range metadata.LinkFiles() {
+		links = append(links, fmt.Sprintf("%s -> %s", filepath.Join(installPath, metadata.BinDir, filepath.Base(script)),
+			filepath.Join(destDir, script)))
+	}
+
+	writeInfoList(out, "Bin links", links)
+
+	dependencies, err := metadata.DependencyPackages()
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Dependencies: %s\n", err)
+
+		return
+	}
+
+	names := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		names = append(names, dependency.String())
+	}
+
+	writeInfoList(out, "Dependencies", names)
+}
+
+func writeInfoList(out io.Writer, title string, items []string) {
+	if 0 == len(items) {
+		_, _ = fmt.Fprintf(out, "%s: none\n", title)
+
+		return
+	}
+
+	_, _ = fmt.Fprintf(out, "%s:\n", title)
+	for _, item := range items {
+		_, _ = fmt.Fprintf(out, "  %s\n", item)
+	}
+}
+
+func prefixPaths(dir string, files []string) []string {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, filepath.Join(dir, file))
+	}
+
+	return paths
+}
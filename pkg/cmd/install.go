@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"github.com/cli/cli/v2/pkg/cmdutil"
 	"github.com/rafaelcalleja/go-bpkg/pkg/repository"
@@ -9,15 +10,32 @@ import (
 	"github.com/rafaelcalleja/go-kit/logger"
 	"github.com/spf13/cobra"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
 type PackageInstallOptions struct {
-	packageName  string
-	installPath  string
-	token        string
-	metadataJson string
-	alias        string
+	packageName    string
+	installPath    string
+	token          string
+	metadataJson   string
+	alias          string
+	dependencyFile string
+	archive        string
+	asset          string
+	assets         map[string]string
+	checksum       string
+	from           string
+	provider       string
+	hostname       string
+	remote         string
+
+	urlTemplate        string
+	indexURL           string
+	insecureSkipVerify bool
+	noCache            bool
+	config             *repository.Config
+	trustStore         *repository.TrustStore
 }
 
 func NewPackageInstall(
@@ -36,81 +54,514 @@ func NewPackageInstall(
 				_ = os.Setenv("GITHUB_TOKEN", o.token)
 			}
 
-			fqpVO, err := repository.NewFullyQualifyPackage(o.packageName)
+			lockFile := repository.LockfilePath(o.installPath)
+			lock, err := repository.NewLockfileFromFileName(lockFile)
 			helper.CheckErr(err)
 
-			if "" == strings.TrimSpace(fqpVO.Version()) {
-				log.Errorf("version is required, package format is [%s] || [%s]", term.ColorInfo("package/name:v1.0.0"), term.ColorInfo("package/name:latest"))
-
-				return
-			}
-
-			releaseVersion, err := repository.NewReleaseVersionWith(
-				repository.ReleaseVersionWithOrganization(fqpVO.Organization()),
-				repository.ReleaseVersionWithName(fqpVO.Name()),
-				repository.ReleaseVersionWithVersion(fqpVO.Version()),
-			)
+			o.config, err = repository.NewConfigFromFileName(repository.DefaultConfigPath())
+			helper.CheckErr(err)
 
-			if fqpVO.Version() == "latest" {
-				releaseVersion, err = repository.NewReleaseLatestVersion(
-					fqpVO.Organization(),
-					fqpVO.Name(),
-					repository.NewGithubVersionFinder(factory),
-				)
+			o.trustStore, err = loadTrustStore(o.config, o.insecureSkipVerify, log, term)
+			helper.CheckErr(err)
 
-				fqpVO = fqpVO.CopyWithVersion(releaseVersion.Version())
+			if "" == strings.TrimSpace(o.packageName) && "" != strings.TrimSpace(o.from) {
+				helper.CheckErr(errors.New("--from requires --package to name the package"))
 			}
 
-			if "" != o.alias {
-				fqpVO = fqpVO.CopyWithName(o.alias)
+			if "" == strings.TrimSpace(o.packageName) {
+				err = installDependencies(o, lock, factory, log, term)
+				helper.CheckErr(lock.Write(lockFile))
+				helper.CheckErr(err)
+
+				return
 			}
 
-			pkgName := fmt.Sprintf("%s-%s", fqpVO.Organization(), fqpVO.Name())
-			packagesInstalled, err := repository.PackagesInstalled(o.installPath)
+			fqpVO, err := repository.NewFullyQualifyPackage(o.packageName)
 			helper.CheckErr(err)
 
-			for _, pkg := range packagesInstalled {
-				if pkg.Name == pkgName && pkg.Version == fqpVO.Version() {
-					log.Infof("Package %s already at version %s", term.ColorInfo(fqpVO.String()),
-						term.ColorInfo(releaseVersion.Version()))
-
-					return
-				}
+			if "" != strings.TrimSpace(o.checksum) {
+				_, err = repository.NewChecksum(o.checksum)
+				helper.CheckErr(err)
 			}
 
-			log.Infof("Installing Package %s at %s", term.ColorInfo(releaseVersion.String()),
-				term.ColorInfo(o.installPath))
+			if "" == strings.TrimSpace(fqpVO.Version()) && "" == strings.TrimSpace(o.from) {
+				log.Errorf("version is required, package format is [%s] || [%s]", term.ColorInfo("package/name:v1.0.0"), term.ColorInfo("package/name:latest"))
 
-			assetGithub := repository.NewGithubProvider(factory)
-			asset, err := releaseVersion.DownloadAsset(assetGithub, o.installPath)
-			if "" != o.alias {
-				asset = asset.CopyWithName(fmt.Sprintf("%s-%s", fqpVO.Organization(), o.alias))
+				return
 			}
 
+			resolver := newInstallResolver(o, lock, factory, log, term)
+			tree, err := resolver.Resolve(fqpVO)
+			helper.CheckErr(lock.Write(lockFile))
 			helper.CheckErr(err)
 
-			if "" != strings.TrimSpace(o.metadataJson) {
-				metadata, err := repository.NewPackageInstallerFromLiteral(o.metadataJson)
-				helper.CheckErr(err)
+			installed := false
+			tree.Walk(func(node *repository.DependencyNode) {
+				installed = installed || node.Installed
+			})
 
-				err = asset.Install(metadata, o.installPath)
-				helper.CheckErr(err)
-			} else {
-				err = releaseVersion.InstallAsset(asset, o.installPath)
-				helper.CheckErr(err)
+			if len(tree.Dependencies) > 0 {
+				log.Infof("Resolved dependency tree:\n%s", tree.String())
 			}
 
-			log.Infof("Installed Successfully")
+			if installed {
+				log.Infof("Installed Successfully")
+			}
 		},
 	}
 
-	newCmd.Flags().StringVar(&o.packageName, "package", "", "[package to install] package/name:v1.0.0")
+	newCmd.Flags().StringVar(&o.packageName, "package", "", "[package to install] package/name:v1.0.0, package/name:^1.2, package/name:latest, ghe.corp.example/package/name:v1.0.0, gitlab:group/sub/name:v1, gitlab://gitlab.corp.example/group/name:v1 or git+ssh://host/repo.git:v1, when empty every dependency of --file is installed")
 	newCmd.Flags().StringVar(&o.installPath, "installPath", "./deps", "[package install path]")
 	newCmd.Flags().StringVar(&o.token, "token", "", "Github Token")
 	newCmd.Flags().StringVar(&o.metadataJson, "metadataJson", "", "overwrite current package.json")
 	newCmd.Flags().StringVar(&o.alias, "alias", "", "package name is replace using alias")
-
-	_ = newCmd.MarkFlagRequired("package")
+	newCmd.Flags().StringVar(&o.archive, "archive", repository.ArchiveFormatTarGz, "source archive format to download: tar.gz or zip")
+	newCmd.Flags().StringVar(&o.asset, "asset", "", "download the release asset matching this pattern instead of the source archive, {{.OS}} and {{.Arch}} are replaced, e.g. mytool_{{.OS}}_{{.Arch}}.tar.gz")
+	newCmd.Flags().StringVar(&o.checksum, "checksum", "", "expected digest of the downloaded archive as sha256:<hex>, checksums published with the release are verified too")
+	newCmd.Flags().StringVar(&o.urlTemplate, "url-template", "", "download --package from an http server, e.g. https://artifacts.corp/{{.Organization}}/{{.Name}}/{{.Version}}.tar.gz")
+	newCmd.Flags().StringVar(&o.indexURL, "index-url", "", "JSON index listing the versions served by --url-template, e.g. https://artifacts.corp/{{.Organization}}/{{.Name}}/index.json")
+	newCmd.Flags().BoolVar(&o.insecureSkipVerify, "insecure-skip-verify", false, "install packages without verifying their signature against the trusted keys of the config, otherwise packages of organizations with neither trustedKeys nor an unsignedOrganizations entry in the config are refused")
+	newCmd.Flags().BoolVar(&o.noCache, "no-cache", false, "download packages again instead of reusing the download cache")
+	newCmd.Flags().StringVar(&o.provider, "provider", "", fmt.Sprintf("releases provider of --package when its spec has no scheme: %s, github by default", strings.Join(repository.RegisteredProviders(), ", ")))
+	newCmd.Flags().StringVar(&o.hostname, "hostname", "", "self-managed host of --provider, e.g. ghe.corp.example or gitlab.corp.example, required by gitea, a host in --package wins")
+	newCmd.Flags().StringVar(&o.remote, "remote", "", "git remote of --package for --provider git, e.g. git@git.corp:{{.Organization}}/{{.Name}}.git, defaults to https://<hostname>/{{.Organization}}/{{.Name}}.git, the dir or file:// url of --provider mirror or the base url of --provider registry")
+	newCmd.Flags().StringVar(&o.from, "from", "", "install --package from a local tar.gz, zip or unpacked directory instead of a remote provider")
+	newCmd.Flags().StringVar(&o.dependencyFile, "file", repository.DefaultDependencyFile, "[project dependency file] used when --package is empty")
 
 	return newCmd
 }
+
+func installDependencies(
+	o *PackageInstallOptions,
+	lock *repository.Lockfile,
+	factory *cmdutil.Factory,
+	log logger.Logger,
+	term termcolor.TermColor,
+) error {
+	if "" != o.alias || "" != strings.TrimSpace(o.metadataJson) || "" != strings.TrimSpace(o.checksum) || "" != o.urlTemplate {
+		return errors.New("--alias, --metadataJson, --checksum and --url-template require --package")
+	}
+
+	dependencyFile, err := repository.NewDependencyFileFromFileName(o.dependencyFile)
+	if err != nil {
+		return err
+	}
+
+	packages, err := dependencyFile.Packages()
+	if err != nil {
+		return err
+	}
+
+	o.assets = dependencyFile.Assets
+
+	resolver := newInstallResolver(o, lock, factory, log, term)
+
+	var installed, skipped, failed []string
+	for _, fqpVO := range packages {
+		tree, err := resolver.Resolve(fqpVO)
+		if err != nil {
+			log.Errorf("Package %s %s: %s", term.ColorInfo(fqpVO.String()), term.ColorError("failed"), err)
+			failed = append(failed, fqpVO.String())
+
+			continue
+		}
+
+		if len(tree.Dependencies) > 0 {
+			log.Infof("Resolved dependency tree:\n%s", tree.String())
+		}
+
+		tree.Walk(func(node *repository.DependencyNode) {
+			switch {
+			case node.Duplicate:
+			case node.Installed:
+				installed = append(installed, node.Package.String())
+			default:
+				skipped = append(skipped, node.Package.String())
+			}
+		})
+	}
+
+	log.Infof("Installed %d, skipped %d, failed %d dependencies from %s", len(installed), len(skipped), len(failed),
+		term.ColorInfo(o.dependencyFile))
+
+	for _, name := range installed {
+		log.Infof("  %s %s", term.ColorInfo("installed"), name)
+	}
+
+	for _, name := range skipped {
+		log.Infof("  %s %s", term.ColorStatus("skipped"), name)
+	}
+
+	for _, name := range failed {
+		log.Infof("  %s %s", term.ColorError("failed"), name)
+	}
+
+	if len(failed) > 0 {
+		return errors.New(fmt.Sprintf("Error Installing %d dependencies", len(failed)))
+	}
+
+	return nil
+}
+
+// newInstallResolver installs the root package with every option and its dependencies without alias or metadata
+// overrides
+func newInstallResolver(
+	o *PackageInstallOptions,
+	lock *repository.Lockfile,
+	factory *cmdutil.Factory,
+	log logger.Logger,
+	term termcolor.TermColor,
+) *repository.DependencyResolver {
+	dependencyOptions := *o
+	dependencyOptions.alias = ""
+	dependencyOptions.metadataJson = ""
+	dependencyOptions.from = ""
+	dependencyOptions.asset = ""
+	dependencyOptions.checksum = ""
+	dependencyOptions.urlTemplate = ""
+	dependencyOptions.indexURL = ""
+
+	dependencyOptions.provider = ""
+	dependencyOptions.hostname = ""
+	dependencyOptions.remote = ""
+
+	return repository.NewDependencyResolver(func(fqpVO repository.FullyQualifyPackage, parent *repository.DependencyFetch) (*repository.DependencyFetch, error) {
+		if nil == parent {
+			return fetchPackage(o, fqpVO, lock, factory, log, term)
+		}
+
+		// dependencies without a provider of their own come from the host of the package declaring them
+		parentOptions := dependencyOptions
+		if "" != parent.Scheme {
+			parentOptions.provider, parentOptions.hostname, parentOptions.remote = parent.Scheme, parent.Hostname, parent.Remote
+		}
+
+		return fetchPackage(&parentOptions, fqpVO, lock, factory, log, term)
+	})
+}
+
+// fetchPackage resolves, downloads and verifies fqpVO, the returned fetch installs it and records it in lock once the
+// whole dependency tree resolved
+func fetchPackage(
+	o *PackageInstallOptions,
+	fqpVO repository.FullyQualifyPackage,
+	lock *repository.Lockfile,
+	factory *cmdutil.Factory,
+	log logger.Logger,
+	term termcolor.TermColor,
+) (*repository.DependencyFetch, error) {
+	if "" == strings.TrimSpace(fqpVO.Version()) && "" == strings.TrimSpace(o.from) {
+		return nil, errors.New(fmt.Sprintf("version is required for package %s", fqpVO.String()))
+	}
+
+	asset := o.assetPattern(fqpVO)
+	scheme, providerOptions := o.providerOptions(factory, fqpVO, asset)
+
+	provider, finder, err := o.releaseProviders(scheme, providerOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	providerName, hostname := repository.DescribeProvider(provider)
+	organization := repository.PackageOrganization(providerName, hostname, fqpVO.Organization())
+	dependencyScheme := o.dependencyScheme(fqpVO, scheme, providerOptions.Hostname)
+
+	// providers serving the dependencies of their packages pass their remote on, any other is reached by its host
+	dependencyRemote := ""
+	if repository.ServesDependencies(provider) {
+		dependencyRemote = providerOptions.Remote
+	}
+
+	spec := fqpVO.String()
+
+	installName := fqpVO.Name()
+	if "" != o.alias {
+		installName = o.alias
+	}
+
+	var releaseVersion repository.ReleaseVersion
+
+	locked, isKnown := lock.Get(repository.LockKey(organization, installName))
+	isLocked := isKnown && locked.Spec == spec && locked.Provider == providerName && locked.Asset == asset
+
+	if isLocked {
+		releaseVersion = repository.NewReleaseVersion(fqpVO.Organization(), fqpVO.Name(), locked.Version)
+		fqpVO = fqpVO.CopyWithVersion(locked.Version)
+	} else {
+		releaseVersion, err = repository.ResolveReleaseVersion(fqpVO, finder)
+		if err != nil {
+			return nil, err
+		}
+
+		fqpVO = fqpVO.CopyWithVersion(releaseVersion.Version())
+	}
+
+	if "" != o.alias {
+		fqpVO = fqpVO.CopyWithName(o.alias)
+	}
+
+	lockedPackage := func(sha256 string) repository.LockedPackage {
+		return repository.LockedPackage{
+			Organization: fqpVO.Organization(),
+			Name:         releaseVersion.Name,
+			Alias:        o.alias,
+			Spec:         spec,
+			Version:      releaseVersion.Version(),
+			Provider:     providerName,
+			Hostname:     hostname,
+			Remote:       providerOptions.Remote,
+			IndexURL:     providerOptions.IndexURL,
+			Asset:        asset,
+			Sha256:       sha256,
+		}
+	}
+
+	pkgName := repository.PackageInstallName(organization, fqpVO.Name())
+	packagesInstalled, err := repository.PackagesInstalled(o.installPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	for _, pkg := range packagesInstalled {
+		if pkg.Name == pkgName && pkg.Version == fqpVO.Version() {
+			log.Infof("Package %s already at version %s", term.ColorInfo(fqpVO.String()),
+				term.ColorInfo(releaseVersion.Version()))
+
+			return &repository.DependencyFetch{
+				Package:  fqpVO,
+				Metadata: pkg,
+				Key:      repository.LockKey(organization, installName),
+				Install: func() (bool, error) {
+					// packages installed before the lockfile existed are recorded too, without the digest of an
+					// archive that wasn't downloaded
+					if false == isLocked {
+						sha256 := ""
+						if isKnown && locked.Version == releaseVersion.Version() {
+							sha256 = locked.Sha256
+						}
+
+						lock.Put(lockedPackage(sha256))
+					}
+
+					return false, nil
+				},
+				Scheme:   dependencyScheme,
+				Hostname: providerOptions.Hostname,
+				Remote:   dependencyRemote,
+			}, nil
+		}
+	}
+
+	log.Infof("Downloading Package %s", term.ColorInfo(releaseVersion.String()))
+
+	releaseAsset, err := releaseVersion.DownloadVerifiedAsset(provider, o.installPath, downloadCache(o.noCache),
+		func(sourceFile string) error {
+			return releaseVersion.VerifyAssetChecksum(provider, sourceFile, o.checksum)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = releaseVersion.VerifyAsset(releaseAsset, assetVerifiers(provider, o.trustStore)...); err != nil {
+		_ = releaseAsset.Remove()
+
+		return nil, err
+	}
+
+	releaseAsset = releaseAsset.CopyWithName(pkgName)
+
+	sha256, err := releaseAsset.Sha256()
+	if err != nil {
+		_ = releaseAsset.Remove()
+
+		return nil, err
+	}
+
+	if isLocked {
+		if err = locked.Verify(sha256); err != nil {
+			_ = releaseAsset.Remove()
+
+			return nil, err
+		}
+	}
+
+	metadata, err := packageMetadata(o, releaseVersion, releaseAsset)
+	if err != nil {
+		_ = releaseAsset.Remove()
+
+		return nil, err
+	}
+
+	install := func() (bool, error) {
+		defer releaseAsset.Remove()
+
+		log.Infof("Installing Package %s at %s", term.ColorInfo(releaseVersion.String()),
+			term.ColorInfo(o.installPath))
+
+		if "" != strings.TrimSpace(o.metadataJson) {
+			if err := releaseAsset.Install(metadata, o.installPath); err != nil {
+				return false, err
+			}
+		} else if err := releaseVersion.InstallAsset(releaseAsset, o.installPath); err != nil {
+			return false, err
+		}
+
+		lock.Put(lockedPackage(sha256))
+
+		return true, nil
+	}
+
+	return &repository.DependencyFetch{
+		Package:  fqpVO,
+		Metadata: metadata,
+		Install:  install,
+		Discard: func() {
+			_ = releaseAsset.Remove()
+		},
+		Key:      repository.LockKey(organization, installName),
+		Scheme:   dependencyScheme,
+		Hostname: providerOptions.Hostname,
+		Remote:   dependencyRemote,
+	}, nil
+}
+
+// packageMetadata is --metadataJson when set, otherwise the manifest of the downloaded package
+func packageMetadata(
+	o *PackageInstallOptions,
+	releaseVersion repository.ReleaseVersion,
+	releaseAsset repository.ReleaseAssets,
+) (*repository.PackageInstaller, error) {
+	if "" != strings.TrimSpace(o.metadataJson) {
+		return repository.NewPackageInstallerFromLiteral(o.metadataJson)
+	}
+
+	metadata, err := releaseVersion.GetPackageMetadata(releaseAsset.DecompressPath())
+	if err != nil {
+		return nil, fmt.Errorf("Error Package Metadata not found at %s: %w",
+			filepath.Join(releaseAsset.DecompressPath(), releaseVersion.Manifest()), repository.ErrPackageManifestNotFound)
+	}
+
+	return metadata, nil
+}
+
+// loadTrustStore reads the trusted publisher keys from the go-bpkg config, nil skips signature verification
+func loadTrustStore(config *repository.Config, insecureSkipVerify bool, log logger.Logger, term termcolor.TermColor) (*repository.TrustStore, error) {
+	if insecureSkipVerify {
+		log.Infof("%s signature verification is disabled", term.ColorWarning("WARNING"))
+
+		return nil, nil
+	}
+
+	return repository.NewTrustStoreFromConfig(config)
+}
+
+// downloadCache is the cache at $GO_BPKG_CACHE or the user cache dir, nil when noCache
+func downloadCache(noCache bool) *repository.DownloadCache {
+	if noCache {
+		return nil
+	}
+
+	return repository.NewDownloadCache(repository.DefaultCachePath())
+}
+
+func assetVerifiers(provider repository.ReleasesProvider, trustStore *repository.TrustStore) []repository.AssetVerifier {
+	if nil == trustStore {
+		return []repository.AssetVerifier{}
+	}
+
+	return []repository.AssetVerifier{repository.NewSignatureVerifier(provider, trustStore)}
+}
+
+// assetPattern is --asset for the package on the command line, otherwise the assets entry of the dependency file,
+// local packages have no release assets
+func (o *PackageInstallOptions) assetPattern(fqpVO repository.FullyQualifyPackage) string {
+	if "" != strings.TrimSpace(o.from) {
+		return ""
+	}
+
+	if "" != strings.TrimSpace(o.asset) {
+		return strings.TrimSpace(o.asset)
+	}
+
+	return o.assets[repository.LockKey(fqpVO.Organization(), fqpVO.Name())]
+}
+
+// providerOptions picks the scheme of the spec, then --provider, then the http source of --url-template or of the
+// organization config, github otherwise
+func (o *PackageInstallOptions) providerOptions(
+	factory *cmdutil.Factory,
+	fqpVO repository.FullyQualifyPackage,
+	asset string,
+) (string, repository.ProviderOptions) {
+	options := repository.ProviderOptions{
+		Hostname: o.packageHostname(fqpVO),
+		URL:      fqpVO.URL(),
+		Remote:   o.remote,
+		Archive:  o.archive,
+		Asset:    asset,
+		Factory:  factory,
+	}
+
+	scheme := fqpVO.Scheme()
+	if "" == scheme {
+		scheme = o.provider
+	}
+
+	source := repository.HttpSource{URLTemplate: o.urlTemplate, IndexURL: o.indexURL}
+	if "" == source.URLTemplate && nil != o.config {
+		source, _ = o.config.HttpSource(fqpVO.Organization())
+	}
+
+	if ("" == scheme && "" != source.URLTemplate) || repository.HttpProviderName == scheme {
+		options.Remote, options.IndexURL = source.URLTemplate, source.IndexURL
+
+		return repository.HttpProviderName, options
+	}
+
+	if "" == scheme {
+		scheme = repository.GithubProviderName
+	}
+
+	return scheme, options
+}
+
+// dependencyScheme is the provider the dependencies of fqpVO inherit, none for github.com so their organizations can
+// still use the http sources of the config, nor for url specs, local packages and http sources, which only serve fqpVO
+func (o *PackageInstallOptions) dependencyScheme(fqpVO repository.FullyQualifyPackage, scheme string, hostname string) string {
+	if "" != strings.TrimSpace(o.from) || "" != fqpVO.URL() || repository.HttpProviderName == scheme {
+		return ""
+	}
+
+	if repository.GithubProviderName == scheme && "" == hostname {
+		return ""
+	}
+
+	return scheme
+}
+
+// releaseProviders returns the provider and version finder pair the package is installed from, --from installs
+// a local package and any other source is built by the provider registered for scheme
+func (o *PackageInstallOptions) releaseProviders(
+	scheme string,
+	options repository.ProviderOptions,
+) (repository.ReleasesProvider, repository.ReleaseVersionFinder, error) {
+	if "" != strings.TrimSpace(o.from) {
+		local, err := repository.NewLocalProvider(o.from)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return local, local, nil
+	}
+
+	return repository.NewProviders(scheme, options)
+}
+
+// packageHostname is the host of the spec, e.g. ghe.corp.example/org/name:v1.0, otherwise --hostname
+func (o *PackageInstallOptions) packageHostname(fqpVO repository.FullyQualifyPackage) string {
+	if "" != fqpVO.Hostname() {
+		return fqpVO.Hostname()
+	}
+
+	return o.hostname
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rafaelcalleja/go-bpkg/pkg/repository"
+	"github.com/rafaelcalleja/go-kit/cmd/helper"
+	"github.com/rafaelcalleja/go-kit/cmd/termcolor"
+	"github.com/rafaelcalleja/go-kit/logger"
+	"github.com/spf13/cobra"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+type PackageListOptions struct {
+	installPath string
+	output      string
+}
+
+type installedPackageView struct {
+	Package   string   `json:"package"`
+	Version   string   `json:"version"`
+	Scripts   []string `json:"scripts"`
+	BinLinks  []string `json:"binLinks"`
+	Directory string   `json:"directory"`
+}
+
+func NewPackageList(
+	helper helper.ErrorHelper,
+	log logger.Logger,
+	term termcolor.TermColor,
+) *cobra.Command {
+	o := &PackageListOptions{}
+
+	newCmd := &cobra.Command{
+		Use:   "list",
+		Short: "BPKG list installed packages",
+		Run: func(cmd *cobra.Command, args []string) {
+			if "table" != o.output && "json" != o.output {
+				helper.CheckErr(errors.New(fmt.Sprintf("unknown output format %s, use table or json", o.output)))
+			}
+
+			views, err := installedPackageViews(o.installPath)
+			helper.CheckErr(err)
+
+			if "json" == o.output {
+				content, err := json.MarshalIndent(views, "", "  ")
+				helper.CheckErr(err)
+
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(content))
+
+				return
+			}
+
+			if 0 == len(views) {
+				log.Infof("No packages installed at %s", term.ColorInfo(o.installPath))
+
+				return
+			}
+
+			writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintln(writer, "PACKAGE\tVERSION\tSCRIPTS\tDIRECTORY")
+			for _, view := range views {
+				_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", view.Package, view.Version, strings.Join(view.Scripts, ","), view.Directory)
+			}
+
+			helper.CheckErr(writer.Flush())
+		},
+	}
+
+	newCmd.Flags().StringVar(&o.installPath, "installPath", "./deps", "[package install path]")
+	newCmd.Flags().StringVarP(&o.output, "output", "o", "table", "output format: table or json")
+
+	return newCmd
+}
+
+// installedPackageViews names every installed package after its lockfile entry, falling back to its directory name
+func installedPackageViews(installPath string) ([]installedPackageView, error) {
+	views := make([]installedPackageView, 0)
+
+	lock, err := repository.NewLockfileFromFileName(repository.LockfilePath(installPath))
+	if err != nil {
+		return views, err
+	}
+
+	packages, err := repository.InstalledPackages(installPath)
+	if err != nil {
+		return views, err
+	}
+
+	for _, pkg := range packages {
+		name := pkg.Name
+		if locked, ok := lock.GetByInstallName(pkg.Name); ok {
+			name = locked.Key()
+		}
+
+		scripts := make([]string, 0)
+		for _, script := range pkg.LinkFiles() {
+			scripts = append(scripts, filepath.Base(script))
+		}
+
+		views = append(views, installedPackageView{
+			Package:   name,
+			Version:   pkg.Version,
+			Scripts:   scripts,
+			BinLinks:  pkg.BinLinks(installPath),
+			Directory: pkg.Dir,
+		})
+	}
+
+	return views, nil
+}
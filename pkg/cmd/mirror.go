@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/rafaelcalleja/go-bpkg/pkg/repository"
+	"github.com/rafaelcalleja/go-kit/cmd/helper"
+	"github.com/rafaelcalleja/go-kit/cmd/termcolor"
+	"github.com/rafaelcalleja/go-kit/logger"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+type PackageMirrorOptions struct {
+	PackageInstallOptions
+	to string
+}
+
+func NewPackageMirror(
+	factory *cmdutil.Factory,
+	helper helper.ErrorHelper,
+	log logger.Logger,
+	term termcolor.TermColor,
+) *cobra.Command {
+	o := &PackageMirrorOptions{}
+
+	newCmd := &cobra.Command{
+		Use:   "mirror --to <dir> package/name:version...",
+		Short: "BPKG download packages and an index into a dir served by --provider mirror",
+		Long: `Download the release archives of the packages, with their signatures when published, and a JSON index into a dir.
+Ship the dir to hosts without internet access and install from it with --provider mirror --remote <dir>.
+Dependencies are not resolved, list them too.`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if "" != strings.TrimSpace(o.token) {
+				_ = os.Setenv("GITHUB_TOKEN", o.token)
+			}
+
+			var err error
+			o.config, err = repository.NewConfigFromFileName(repository.DefaultConfigPath())
+			helper.CheckErr(err)
+
+			index, err := repository.NewMirrorIndexFromDir(o.to)
+			helper.CheckErr(err)
+
+			mirrored, failed := 0, 0
+			for _, packageName := range args {
+				if err = mirrorPackage(o, index, packageName, factory, log, term); err != nil {
+					failed++
+					log.Errorf("Package %s %s: %s", term.ColorInfo(packageName), term.ColorError("mirror failed"), err)
+
+					continue
+				}
+
+				mirrored++
+				helper.CheckErr(index.Write())
+			}
+
+			log.Infof("Mirrored %d packages to %s, %d failed", mirrored, term.ColorInfo(o.to), failed)
+
+			if failed > 0 {
+				helper.CheckErr(errors.New(fmt.Sprintf("Error Mirroring %d packages", failed)))
+			}
+		},
+	}
+
+	newCmd.Flags().StringVar(&o.to, "to", "", "mirror dir, created when missing, an existing index is updated")
+	newCmd.Flags().StringVar(&o.token, "token", "", "Github Token")
+	newCmd.Flags().StringVar(&o.archive, "archive", repository.ArchiveFormatTarGz, "source archive format to download: tar.gz or zip")
+	newCmd.Flags().StringVar(&o.asset, "asset", "", "download the release asset matching this pattern instead of the source archive, {{.OS}} and {{.Arch}} are replaced, e.g. mytool_{{.OS}}_{{.Arch}}.tar.gz")
+	newCmd.Flags().StringVar(&o.urlTemplate, "url-template", "", "download the packages from an http server, e.g. https://artifacts.corp/{{.Organization}}/{{.Name}}/{{.Version}}.tar.gz")
+	newCmd.Flags().StringVar(&o.indexURL, "index-url", "", "JSON index listing the versions served by --url-template")
+	newCmd.Flags().StringVar(&o.provider, "provider", "", fmt.Sprintf("releases provider of the packages whose spec has no scheme: %s, github by default", strings.Join(repository.RegisteredProviders(), ", ")))
+	newCmd.Flags().StringVar(&o.hostname, "hostname", "", "self-managed host of --provider, e.g. ghe.corp.example or gitlab.corp.example, a host in the spec wins")
+	newCmd.Flags().StringVar(&o.remote, "remote", "", "git remote for --provider git, e.g. git@git.corp:{{.Organization}}/{{.Name}}.git")
+
+	_ = newCmd.MarkFlagRequired("to")
+
+	return newCmd
+}
+
+// mirrorPackage resolves the version of packageName with its provider and adds it to index
+func mirrorPackage(
+	o *PackageMirrorOptions,
+	index *repository.MirrorIndex,
+	packageName string,
+	factory *cmdutil.Factory,
+	log logger.Logger,
+	term termcolor.TermColor,
+) error {
+	fqpVO, err := repository.NewFullyQualifyPackage(packageName)
+	if err != nil {
+		return err
+	}
+
+	if "" == strings.TrimSpace(fqpVO.Version()) {
+		return errors.New(fmt.Sprintf("version is required for package %s", fqpVO.String()))
+	}
+
+	scheme, providerOptions := o.providerOptions(factory, fqpVO, o.assetPattern(fqpVO))
+
+	provider, finder, err := o.releaseProviders(scheme, providerOptions)
+	if err != nil {
+		return err
+	}
+
+	releaseVersion, err := repository.ResolveReleaseVersion(fqpVO, finder)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Mirroring Package %s", term.ColorInfo(fqpVO.CopyWithVersion(releaseVersion.Version()).String()))
+
+	release, err := index.Mirror(provider, &releaseVersion)
+	if err != nil {
+		return err
+	}
+
+	providerName, hostname := repository.DescribeProvider(provider)
+	log.Infof("Package %s mirrored at %s",
+		term.ColorInfo(repository.LockKey(repository.PackageOrganization(providerName, hostname, fqpVO.Organization()), fqpVO.Name())),
+		term.ColorInfo(release.File))
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/rafaelcalleja/go-bpkg/pkg/repository"
+	"github.com/rafaelcalleja/go-kit/cmd/helper"
+	"github.com/rafaelcalleja/go-kit/cmd/termcolor"
+	"github.com/rafaelcalleja/go-kit/logger"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+type PackageOutdatedOptions struct {
+	installPath string
+	token       string
+	concurrency int
+}
+
+func NewPackageOutdated(
+	factory *cmdutil.Factory,
+	helper helper.ErrorHelper,
+	log logger.Logger,
+	term termcolor.TermColor,
+) *cobra.Command {
+	o := &PackageOutdatedOptions{}
+
+	newCmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "BPKG list installed packages behind their latest release",
+		Run: func(cmd *cobra.Command, args []string) {
+			if "" != strings.TrimSpace(o.token) {
+				_ = os.Setenv("GITHUB_TOKEN", o.token)
+			}
+
+			lock, err := repository.NewLockfileFromFileName(repository.LockfilePath(o.installPath))
+			helper.CheckErr(err)
+
+			results, err := repository.CheckOutdatedInstalled(o.installPath, lock, lockedVersionFinder(factory), o.concurrency)
+			helper.CheckErr(err)
+
+			behind, unlocked, failed := 0, 0, 0
+			writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintln(writer, "PACKAGE\tCURRENT\tWANTED\tLATEST")
+			for _, result := range results {
+				if nil != result.Err {
+					failed++
+					log.Errorf("Package %s %s: %s", term.ColorInfo(result.Name()), term.ColorError("lookup failed"), result.Err)
+
+					continue
+				}
+
+				if result.IsUnlocked() {
+					unlocked++
+				} else if false == result.IsBehind() {
+					continue
+				} else {
+					behind++
+				}
+
+				_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", result.Name(), result.Current, result.Wanted, result.Latest)
+			}
+
+			if 0 == behind && 0 == unlocked && 0 == failed {
+				log.Infof("All packages at %s are up to date", term.ColorInfo(o.installPath))
+
+				return
+			}
+
+			if behind > 0 || unlocked > 0 {
+				helper.CheckErr(writer.Flush())
+			}
+
+			if unlocked > 0 {
+				log.Infof("%d packages %s, install them again to record where they come from", unlocked, term.ColorWarning("missing from the lockfile"))
+			}
+
+			if behind > 0 || failed > 0 {
+				helper.CheckErr(errors.New(fmt.Sprintf("%d packages outdated, %d lookups failed", behind, failed)))
+			}
+		},
+	}
+
+	newCmd.Flags().StringVar(&o.installPath, "installPath", "./deps", "[package install path]")
+	newCmd.Flags().StringVar(&o.token, "token", "", "Github Token")
+	newCmd.Flags().IntVar(&o.concurrency, "concurrency", repository.DefaultOutdatedConcurrency, "maximum number of release lookups in flight")
+
+	return newCmd
+}
+
+// lockedInstalledPackages returns the lockfile entry of every installed package, packages missing from the lockfile
+// are skipped because their organization and name can't be recovered from the install directory
+func lockedInstalledPackages(installPath string, log logger.Logger, term termcolor.TermColor) ([]repository.LockedPackage, error) {
+	lockedPackages := make([]repository.LockedPackage, 0)
+
+	lock, err := repository.NewLockfileFromFileName(repository.LockfilePath(installPath))
+	if err != nil {
+		return lockedPackages, err
+	}
+
+	packages, err := repository.InstalledPackages(installPath)
+	if err != nil {
+		return lockedPackages, err
+	}
+
+	for _, pkg := range packages {
+		locked, ok := lock.GetByInstallName(pkg.Name)
+		if !ok {
+			log.Infof("Package %s %s", term.ColorInfo(pkg.Name), term.ColorWarning("skipped, not found in lockfile"))
+
+			continue
+		}
+
+		lockedPackages = append(lockedPackages, locked)
+	}
+
+	return lockedPackages, nil
+}
+
+// lockedVersionFinder looks up every package with the provider it was installed from
+func lockedVersionFinder(factory *cmdutil.Factory) func(repository.LockedPackage) (repository.ReleaseVersionFinder, error) {
+	return func(locked repository.LockedPackage) (repository.ReleaseVersionFinder, error) {
+		_, finder, err := lockedProviders(factory, locked)
+
+		return finder, err
+	}
+}
+
+// lockedProviders rebuilds the provider and version finder pair of locked, older lockfiles without provider are github
+func lockedProviders(
+	factory *cmdutil.Factory,
+	locked repository.LockedPackage,
+) (repository.ReleasesProvider, repository.ReleaseVersionFinder, error) {
+	scheme := locked.Provider
+	if "" == scheme {
+		scheme = repository.GithubProviderName
+	}
+
+	options := repository.ProviderOptions{
+		Hostname: locked.Hostname,
+		Remote:   locked.Remote,
+		IndexURL: locked.IndexURL,
+		Asset:    locked.Asset,
+		Factory:  factory,
+	}
+
+	if spec, err := repository.NewFullyQualifyPackage(locked.Spec); err == nil {
+		options.URL = spec.URL()
+	}
+
+	return repository.NewProviders(scheme, options)
+}
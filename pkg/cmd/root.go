@@ -3,6 +3,7 @@ package cmd
 import (
 	ghfactory "github.com/cli/cli/v2/pkg/cmd/factory"
 	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/rafaelcalleja/go-bpkg/pkg/cmd/cache"
 	"github.com/rafaelcalleja/go-bpkg/pkg/cmd/github"
 	"github.com/rafaelcalleja/go-bpkg/pkg/rootcmd"
 	"github.com/rafaelcalleja/go-kit/cmd/cobra/version"
@@ -36,6 +37,13 @@ func Main() *cobra.Command {
 	cmd.AddCommand(version.NewCmdVersion(errorHelper, log, term))
 	cmd.AddCommand(NewPackageInstall(factory, errorHelper, log, term))
 	cmd.AddCommand(NewPackageUninstall(errorHelper, log, term))
+	cmd.AddCommand(NewPackageList(errorHelper, log, term))
+	cmd.AddCommand(NewPackageOutdated(factory, errorHelper, log, term))
+	cmd.AddCommand(NewPackageUpgrade(factory, errorHelper, log, term))
+	cmd.AddCommand(NewPackageInfo(factory, errorHelper, log, term))
+	cmd.AddCommand(NewPackageMirror(factory, errorHelper, log, term))
+	cmd.AddCommand(NewPackageServe(errorHelper, log, term))
+	cmd.AddCommand(cache.NewCmdCache(errorHelper, log, term))
 	cmd.AddCommand(github.NewCmdGithub(factory, errorHelper))
 
 	return cmd
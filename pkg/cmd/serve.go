@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"github.com/rafaelcalleja/go-bpkg/pkg/repository"
+	"github.com/rafaelcalleja/go-kit/cmd/helper"
+	"github.com/rafaelcalleja/go-kit/cmd/termcolor"
+	"github.com/rafaelcalleja/go-kit/logger"
+	"github.com/spf13/cobra"
+	"net/http"
+	"time"
+)
+
+const serveReadHeaderTimeout = 10 * time.Second
+
+type PackageServeOptions struct {
+	root string
+	addr string
+}
+
+func NewPackageServe(
+	helper helper.ErrorHelper,
+	log logger.Logger,
+	term termcolor.TermColor,
+) *cobra.Command {
+	o := &PackageServeOptions{}
+
+	newCmd := &cobra.Command{
+		Use:   "serve --root <dir>",
+		Short: "BPKG serve a mirror dir as a read-only package registry",
+		Long: `Serve the packages of a dir written by the mirror command over http with the v1 registry API:
+
+  GET /v1/packages                                    packages of the registry
+  GET /v1/packages/<org>/<name>                       releases of a package
+  GET /v1/packages/<org>/<name>/<version>/archive     release file
+  GET /v1/packages/<org>/<name>/<version>/signature   release file signature
+
+Install from it with --provider registry --remote http://<addr>, or with registry://<host>/org/name:version specs
+when it is served over https.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			registry, err := repository.NewRegistryServer(o.root)
+			helper.CheckErr(err)
+
+			server := &http.Server{
+				Addr:              o.addr,
+				Handler:           registry,
+				ReadHeaderTimeout: serveReadHeaderTimeout,
+			}
+
+			log.Infof("Serving packages of %s at %s", term.ColorInfo(o.root), term.ColorInfo(o.addr))
+
+			helper.CheckErr(server.ListenAndServe())
+		},
+	}
+
+	newCmd.Flags().StringVar(&o.root, "root", "", "mirror dir to serve, see the mirror command")
+	newCmd.Flags().StringVar(&o.addr, "addr", ":8080", "address to listen on")
+
+	_ = newCmd.MarkFlagRequired("root")
+
+	return newCmd
+}
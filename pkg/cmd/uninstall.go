@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	"fmt"
+	"errors"
 	"github.com/rafaelcalleja/go-bpkg/pkg/repository"
 	"github.com/rafaelcalleja/go-kit/cmd/helper"
 	"github.com/rafaelcalleja/go-kit/cmd/termcolor"
@@ -29,28 +29,51 @@ func NewPackageUninstall(
 
 			fqpVO, err := repository.NewFullyQualifyPackage(o.packageName)
 			helper.CheckErr(err)
-			pkgName := fmt.Sprintf("%s-%s", fqpVO.Organization(), fqpVO.Name())
 
-			packagesInstalled, err := repository.PackagesInstalled(o.installPath)
+			lockFile := repository.LockfilePath(o.installPath)
+			lock, err := repository.NewLockfileFromFileName(lockFile)
+			helper.CheckErr(err)
+
+			// the lockfile knows the provider and host the package was installed from, packages installed before it
+			// existed are only found by the directory their spec maps to
+			organization := repository.PackageOrganization(fqpVO.Scheme(), fqpVO.Hostname(), fqpVO.Organization())
+			key, pkgName := repository.LockKey(organization, fqpVO.Name()), repository.PackageInstallName(organization, fqpVO.Name())
+
+			locked, err := lock.Find(fqpVO)
+			if errors.Is(err, repository.ErrAmbiguousLockedPackage) {
+				helper.CheckErr(err)
+			}
+
+			isLocked := err == nil
+			if isLocked {
+				key, pkgName = locked.Key(), locked.InstallName()
+			}
+
+			packagesInstalled, err := repository.InstalledPackages(o.installPath)
 			helper.CheckErr(err)
 
 			for _, pkg := range packagesInstalled {
-				if pkg.Name == pkgName && pkg.Version == fqpVO.Version() {
-					err = pkg.Uninstall(filepath.Join(o.installPath, pkgName))
-					helper.CheckErr(err)
-					log.Infof("Package %s:%s uninstalled!", term.ColorInfo(fmt.Sprintf("%s/%s", fqpVO.Organization(), fqpVO.Name())),
-						term.ColorInfo(fqpVO.Version()))
+				if pkg.Name != pkgName || (pkg.Version != fqpVO.Version() && (false == isLocked || locked.Version != fqpVO.Version())) {
+					continue
+				}
+
+				helper.CheckErr(pkg.Uninstall(filepath.Join(o.installPath, pkgName)))
 
-					return
+				if isLocked {
+					lock.Remove(key)
+					helper.CheckErr(lock.Write(lockFile))
 				}
+
+				log.Infof("Package %s:%s uninstalled!", term.ColorInfo(key), term.ColorInfo(fqpVO.Version()))
+
+				return
 			}
 
-			log.Infof("Package %s:%s %s!", term.ColorInfo(fmt.Sprintf("%s/%s", fqpVO.Organization(), fqpVO.Name())),
-				term.ColorInfo(fqpVO.Version()), term.ColorError("not found"))
+			log.Infof("Package %s:%s %s!", term.ColorInfo(key), term.ColorInfo(fqpVO.Version()), term.ColorError("not found"))
 		},
 	}
 
-	newCmd.Flags().StringVar(&o.packageName, "package", "", "[package to uninstall] package/name:v1.0.0")
+	newCmd.Flags().StringVar(&o.packageName, "package", "", "[package to uninstall] package/name:v1.0.0, found in the lockfile whatever --provider and --hostname it was installed with, a scheme or host picks one of several, e.g. gitlab://gitlab.corp.example/group/sub/name:v1.0.0")
 	newCmd.Flags().StringVar(&o.installPath, "installPath", "./deps", "[package install path]")
 
 	_ = newCmd.MarkFlagRequired("package")
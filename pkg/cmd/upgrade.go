@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/rafaelcalleja/go-bpkg/pkg/repository"
+	"github.com/rafaelcalleja/go-kit/cmd/helper"
+	"github.com/rafaelcalleja/go-kit/cmd/termcolor"
+	"github.com/rafaelcalleja/go-kit/logger"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+type PackageUpgradeOptions struct {
+	installPath        string
+	token              string
+	insecureSkipVerify bool
+	noCache            bool
+	trustStore         *repository.TrustStore
+}
+
+func NewPackageUpgrade(
+	factory *cmdutil.Factory,
+	helper helper.ErrorHelper,
+	log logger.Logger,
+	term termcolor.TermColor,
+) *cobra.Command {
+	o := &PackageUpgradeOptions{}
+
+	newCmd := &cobra.Command{
+		Use:   "upgrade [package/name[:version]]",
+		Short: "BPKG upgrade installed packages to the newest allowed version",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if "" != strings.TrimSpace(o.token) {
+				_ = os.Setenv("GITHUB_TOKEN", o.token)
+			}
+
+			lockFile := repository.LockfilePath(o.installPath)
+			lock, err := repository.NewLockfileFromFileName(lockFile)
+			helper.CheckErr(err)
+
+			config, err := repository.NewConfigFromFileName(repository.DefaultConfigPath())
+			helper.CheckErr(err)
+
+			o.trustStore, err = loadTrustStore(config, o.insecureSkipVerify, log, term)
+			helper.CheckErr(err)
+
+			packages, err := lockedInstalledPackages(o.installPath, log, term)
+			helper.CheckErr(err)
+
+			if 1 == len(args) {
+				packages, err = selectUpgradePackage(packages, args[0])
+				helper.CheckErr(err)
+			}
+
+			results := repository.CheckOutdatedWith(packages, lockedVersionFinder(factory), repository.DefaultOutdatedConcurrency)
+
+			upgraded, failed := 0, 0
+			for _, result := range results {
+				if nil == result.Err && result.Wanted == result.Current {
+					log.Infof("Package %s already at newest allowed version %s", term.ColorInfo(result.Package.Key()),
+						term.ColorInfo(result.Current))
+
+					continue
+				}
+
+				if nil == result.Err {
+					result.Err = upgradePackage(o, result.Package, result.Wanted, lock, factory, log, term)
+				}
+
+				if nil != result.Err {
+					failed++
+					log.Errorf("Package %s %s: %s", term.ColorInfo(result.Package.Key()), term.ColorError("upgrade failed"), result.Err)
+
+					continue
+				}
+
+				upgraded++
+				helper.CheckErr(lock.Write(lockFile))
+			}
+
+			log.Infof("Upgraded %d packages, %d failed", upgraded, failed)
+
+			if failed > 0 {
+				helper.CheckErr(errors.New(fmt.Sprintf("Error Upgrading %d packages", failed)))
+			}
+		},
+	}
+
+	newCmd.Flags().StringVar(&o.installPath, "installPath", "./deps", "[package install path]")
+	newCmd.Flags().StringVar(&o.token, "token", "", "Github Token")
+	newCmd.Flags().BoolVar(&o.insecureSkipVerify, "insecure-skip-verify", false, "upgrade packages without verifying their signature against the trusted keys of the config, otherwise packages of organizations with neither trustedKeys nor an unsignedOrganizations entry in the config are refused")
+	newCmd.Flags().BoolVar(&o.noCache, "no-cache", false, "download packages again instead of reusing the download cache")
+
+	return newCmd
+}
+
+// selectUpgradePackage keeps the package named by packageName, a version in packageName replaces its locked spec
+func selectUpgradePackage(packages []repository.LockedPackage, packageName string) ([]repository.LockedPackage, error) {
+	fqpVO, err := repository.NewFullyQualifyPackage(packageName)
+	if err != nil {
+		return packages, err
+	}
+
+	organization := repository.PackageOrganization(fqpVO.Scheme(), fqpVO.Hostname(), fqpVO.Organization())
+
+	for _, locked := range packages {
+		if locked.Key() != repository.LockKey(organization, fqpVO.Name()) {
+			continue
+		}
+
+		if "" != fqpVO.Version() {
+			locked.Spec = lockedSpecWithVersion(locked, fqpVO.Version())
+		}
+
+		return []repository.LockedPackage{locked}, nil
+	}
+
+	return packages, fmt.Errorf("%w: %s", repository.ErrPackageNotInstalled, packageName)
+}
+
+func upgradePackage(
+	o *PackageUpgradeOptions,
+	locked repository.LockedPackage,
+	version string,
+	lock *repository.Lockfile,
+	factory *cmdutil.Factory,
+	log logger.Logger,
+	term termcolor.TermColor,
+) error {
+	releaseVersion := repository.NewReleaseVersion(locked.Organization, locked.Name, version)
+
+	log.Infof("Upgrading Package %s from %s to %s", term.ColorInfo(locked.Key()), term.ColorInfo(locked.Version),
+		term.ColorInfo(version))
+
+	provider, _, err := lockedProviders(factory, locked)
+	if err != nil {
+		return err
+	}
+
+	asset, err := releaseVersion.DownloadVerifiedAsset(provider, o.installPath, downloadCache(o.noCache),
+		func(sourceFile string) error {
+			return releaseVersion.VerifyAssetChecksum(provider, sourceFile, "")
+		})
+	if err != nil {
+		return err
+	}
+
+	defer asset.Remove()
+
+	if err = releaseVersion.VerifyAsset(asset, assetVerifiers(provider, o.trustStore)...); err != nil {
+		return err
+	}
+
+	asset = asset.CopyWithName(locked.InstallName())
+
+	sha256, err := asset.Sha256()
+	if err != nil {
+		return err
+	}
+
+	if err = releaseVersion.UpgradeAsset(asset, o.installPath); err != nil {
+		return err
+	}
+
+	locked.Version = version
+	locked.Sha256 = sha256
+	locked.Provider, locked.Hostname = repository.DescribeProvider(provider)
+	lock.Put(locked)
+
+	return nil
+}
+
+// lockedSpecWithVersion replaces the version of the locked spec and keeps its scheme and hostname
+func lockedSpecWithVersion(locked repository.LockedPackage, version string) string {
+	if spec, err := repository.NewFullyQualifyPackage(locked.Spec); err == nil {
+		return spec.CopyWithVersion(version).String()
+	}
+
+	return fmt.Sprintf("%s:%s", repository.LockKey(locked.Organization, locked.Name), version)
+}
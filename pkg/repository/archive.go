@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	ArchiveFormatTarGz = "tar.gz"
+	ArchiveFormatZip   = "zip"
+)
+
+var (
+	ErrUnknownArchiveFormat = errors.New("unknown archive format")
+
+	gzipMagic     = []byte{0x1f, 0x8b}
+	zipMagic      = []byte("PK\x03\x04")
+	zipEmptyMagic = []byte("PK\x05\x06")
+)
+
+// DetectArchiveFormat reads the magic bytes of filePath, the file extension is ignored
+func DetectArchiveFormat(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Error can't open file %s", filePath))
+	}
+
+	defer file.Close()
+
+	header := make([]byte, 4)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("%w: %s", ErrUnknownArchiveFormat, filePath)
+	}
+
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		return ArchiveFormatTarGz, nil
+	case bytes.HasPrefix(header, zipMagic), bytes.HasPrefix(header, zipEmptyMagic):
+		return ArchiveFormatZip, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownArchiveFormat, filePath)
+	}
+}
+
+func ExtractArchive(archive string, target string) error {
+	return ExtractArchiveWith(archive, target, DefaultExtractLimits)
+}
+
+// ExtractArchiveWith extracts tar.gz and zip archives rejecting any entry that could write outside target
+func ExtractArchiveWith(archive string, target string, limits ExtractLimits) error {
+	format, err := DetectArchiveFormat(archive)
+	if err != nil {
+		return err
+	}
+
+	root, err := filepath.Abs(target)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error getting absolute path of %s", target))
+	}
+
+	if err = os.MkdirAll(root, 0755); err != nil {
+		return errors.New(fmt.Sprintf("Error Creating dir %s", root))
+	}
+
+	extractor := &archiveExtractor{root: root, limits: limits}
+
+	switch format {
+	case ArchiveFormatZip:
+		return extractor.unzipAll(archive)
+	default:
+		return extractor.untarAll(archive)
+	}
+}
+
+// CreateTarGz archives srcDir into archivePath under a top level folder named after srcDir, .git folders are skipped
+func CreateTarGz(srcDir string, archivePath string) error {
+	srcDir, err := filepath.Abs(srcDir)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error getting absolute path of %s", srcDir))
+	}
+
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error Creating archive %s", archivePath))
+	}
+
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err = filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && ".git" == info.Name() {
+			return filepath.SkipDir
+		}
+
+		relativePath, err := filepath.Rel(filepath.Dir(srcDir), path)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(relativePath)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err = tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if false == info.Mode().IsRegular() {
+			return nil
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		defer src.Close()
+
+		_, err = io.Copy(tarWriter, src)
+
+		return err
+	})
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error Creating archive %s from %s: %s", archivePath, srcDir, err))
+	}
+
+	if err = tarWriter.Close(); err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
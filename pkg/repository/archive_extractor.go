@@ -0,0 +1,349 @@
+package repository
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrArchiveAbsolutePath     = errors.New("archive entry has an absolute path")
+	ErrArchivePathTraversal    = errors.New("archive entry escapes the extraction root")
+	ErrArchiveLinkOutsideRoot  = errors.New("archive link points outside the extraction root")
+	ErrArchiveWriteThroughLink = errors.New("archive entry is written through a symlink")
+	ErrArchiveUnsupportedEntry = errors.New("archive entry type not supported")
+	ErrArchiveTooLarge         = errors.New("archive exceeds the maximum extracted size")
+	ErrArchiveTooManyEntries   = errors.New("archive exceeds the maximum number of entries")
+
+	DefaultExtractLimits = ExtractLimits{MaxTotalSize: 512 << 20, MaxEntries: 10000}
+)
+
+type ExtractLimits struct {
+	MaxTotalSize int64
+	MaxEntries   int
+}
+
+// ArchiveEntryError names the archive entry that was rejected, Err is one of the ErrArchive errors
+type ArchiveEntryError struct {
+	Entry string
+	Err   error
+}
+
+func (e *ArchiveEntryError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Err, e.Entry)
+}
+
+func (e *ArchiveEntryError) Unwrap() error {
+	return e.Err
+}
+
+type archiveExtractor struct {
+	root      string
+	limits    ExtractLimits
+	entries   int
+	totalSize int64
+}
+
+func (e *archiveExtractor) untarAll(archive string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err = e.countEntry(header.Name); err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeXGlobalHeader:
+			continue
+		case tar.TypeDir:
+			err = e.dir(header.Name, header.FileInfo().Mode())
+		case tar.TypeReg, tar.TypeRegA:
+			err = e.file(header.Name, header.FileInfo().Mode(), tarReader)
+		case tar.TypeSymlink:
+			err = e.symlink(header.Name, header.Linkname)
+		case tar.TypeLink:
+			err = e.hardlink(header.Name, header.Linkname)
+		default:
+			err = &ArchiveEntryError{Entry: header.Name, Err: ErrArchiveUnsupportedEntry}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (e *archiveExtractor) unzipAll(archive string) error {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if err = e.countEntry(file.Name); err != nil {
+			return err
+		}
+
+		if err = e.unzipEntry(file); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unzipEntry keeps the unix permission bits stored in the external attributes of the entry
+func (e *archiveExtractor) unzipEntry(file *zip.File) error {
+	mode := file.FileInfo().Mode()
+
+	switch {
+	case mode.IsDir():
+		return e.dir(file.Name, mode)
+	case mode&os.ModeSymlink != 0:
+		src, err := file.Open()
+		if err != nil {
+			return err
+		}
+
+		defer src.Close()
+
+		linkname, err := io.ReadAll(io.LimitReader(src, 4096))
+		if err != nil {
+			return err
+		}
+
+		return e.symlink(file.Name, string(linkname))
+	case mode.IsRegular():
+		src, err := file.Open()
+		if err != nil {
+			return err
+		}
+
+		defer src.Close()
+
+		return e.file(file.Name, mode, src)
+	default:
+		return &ArchiveEntryError{Entry: file.Name, Err: ErrArchiveUnsupportedEntry}
+	}
+}
+
+func (e *archiveExtractor) countEntry(name string) error {
+	e.entries++
+
+	if e.limits.MaxEntries > 0 && e.entries > e.limits.MaxEntries {
+		return &ArchiveEntryError{Entry: name, Err: ErrArchiveTooManyEntries}
+	}
+
+	return nil
+}
+
+// path validates name and returns where it is extracted, no parent of it inside root may be a symlink
+func (e *archiveExtractor) path(name string) (string, error) {
+	slashed := filepath.ToSlash(name)
+
+	if path.IsAbs(slashed) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", &ArchiveEntryError{Entry: name, Err: ErrArchiveAbsolutePath}
+	}
+
+	for _, component := range strings.Split(slashed, "/") {
+		if ".." == component {
+			return "", &ArchiveEntryError{Entry: name, Err: ErrArchivePathTraversal}
+		}
+	}
+
+	target := filepath.Join(e.root, filepath.FromSlash(slashed))
+	if false == e.inRoot(target) {
+		return "", &ArchiveEntryError{Entry: name, Err: ErrArchivePathTraversal}
+	}
+
+	relative, _ := filepath.Rel(e.root, filepath.Dir(target))
+	current := e.root
+	for _, component := range strings.Split(relative, string(filepath.Separator)) {
+		if "." == component || "" == component {
+			continue
+		}
+
+		current = filepath.Join(current, component)
+		if info, err := os.Lstat(current); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			return "", &ArchiveEntryError{Entry: name, Err: ErrArchiveWriteThroughLink}
+		}
+	}
+
+	return target, nil
+}
+
+func (e *archiveExtractor) inRoot(target string) bool {
+	target = filepath.Clean(target)
+
+	return target == e.root || strings.HasPrefix(target, e.root+string(filepath.Separator))
+}
+
+// removeExisting lets a later entry replace an earlier file or link without following it
+func (e *archiveExtractor) removeExisting(target string) error {
+	info, err := os.Lstat(target)
+	if err != nil || info.IsDir() {
+		return nil
+	}
+
+	return os.Remove(target)
+}
+
+func (e *archiveExtractor) dir(name string, mode os.FileMode) error {
+	target, err := e.path(name)
+	if err != nil {
+		return err
+	}
+
+	if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return &ArchiveEntryError{Entry: name, Err: ErrArchiveWriteThroughLink}
+	}
+
+	return os.MkdirAll(target, mode.Perm()|0700)
+}
+
+// file drops setuid, setgid and sticky bits and stops copying once the size limit is exceeded
+func (e *archiveExtractor) file(name string, mode os.FileMode, reader io.Reader) error {
+	target, err := e.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if err = e.removeExisting(target); err != nil {
+		return err
+	}
+
+	perm := mode.Perm()
+	if 0 == perm {
+		perm = 0644
+	}
+
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+
+	defer dst.Close()
+
+	if e.limits.MaxTotalSize > 0 {
+		reader = io.LimitReader(reader, e.limits.MaxTotalSize-e.totalSize+1)
+	}
+
+	written, err := io.Copy(dst, reader)
+	e.totalSize += written
+
+	if err != nil {
+		return err
+	}
+
+	if e.limits.MaxTotalSize > 0 && e.totalSize > e.limits.MaxTotalSize {
+		return &ArchiveEntryError{Entry: name, Err: ErrArchiveTooLarge}
+	}
+
+	return nil
+}
+
+// symlink only accepts a linkname whose .. components lead it. The dir of the link has no symlink in it, see path, so
+// the leading .. resolve lexically, and a .. after a name could go back through a link extracted before or after this
+// one, e.g. d/l -> .. and l2 -> d/l/../secret. The names after the leading .. only go down through links that resolve
+// inside root as well
+func (e *archiveExtractor) symlink(name string, linkname string) error {
+	target, err := e.path(name)
+	if err != nil {
+		return err
+	}
+
+	if filepath.IsAbs(linkname) || path.IsAbs(filepath.ToSlash(linkname)) {
+		return &ArchiveEntryError{Entry: name, Err: ErrArchiveLinkOutsideRoot}
+	}
+
+	named := false
+	for _, component := range strings.Split(filepath.ToSlash(linkname), "/") {
+		switch component {
+		case "", ".":
+		case "..":
+			if named {
+				return &ArchiveEntryError{Entry: name, Err: ErrArchiveLinkOutsideRoot}
+			}
+		default:
+			named = true
+		}
+	}
+
+	if false == e.inRoot(filepath.Join(filepath.Dir(target), filepath.FromSlash(linkname))) {
+		return &ArchiveEntryError{Entry: name, Err: ErrArchiveLinkOutsideRoot}
+	}
+
+	if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if err = e.removeExisting(target); err != nil {
+		return err
+	}
+
+	return os.Symlink(linkname, target)
+}
+
+// hardlink names are relative to the archive root like any other entry
+func (e *archiveExtractor) hardlink(name string, linkname string) error {
+	target, err := e.path(name)
+	if err != nil {
+		return err
+	}
+
+	source, err := e.path(linkname)
+	if err != nil {
+		return &ArchiveEntryError{Entry: name, Err: ErrArchiveLinkOutsideRoot}
+	}
+
+	info, err := os.Lstat(source)
+	if err != nil || false == info.Mode().IsRegular() {
+		return &ArchiveEntryError{Entry: name, Err: ErrArchiveLinkOutsideRoot}
+	}
+
+	if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if err = e.removeExisting(target); err != nil {
+		return err
+	}
+
+	return os.Link(source, target)
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectArchiveFormat(t *testing.T) {
+	formats := map[string]string{
+		"testdata/sourceTarFile.tar.gz": ArchiveFormatTarGz,
+		"testdata/sourceZipFile.zip":    ArchiveFormatZip,
+	}
+
+	for archive, expected := range formats {
+		format, err := DetectArchiveFormat(archive)
+		require.Nil(t, err)
+		assert.Equal(t, expected, format)
+	}
+
+	_, err := DetectArchiveFormat("testdata/package.json")
+	assert.True(t, errors.Is(err, ErrUnknownArchiveFormat))
+
+	_, err = DetectArchiveFormat("testdata/not_found.zip")
+	assert.NotNil(t, err)
+}
+
+func TestExtractArchiveFormats(t *testing.T) {
+	for _, archive := range []string{"testdata/sourceTarFile.tar.gz", "testdata/sourceZipFile.zip"} {
+		tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+		defer os.RemoveAll(tempFolder)
+
+		asset := NewReleaseAssets("testName", "v1.1", archive, tempFolder)
+		assert.Equal(t, "assert.sh-1.1", asset.PackageFolder(), archive)
+
+		info, err := os.Stat(filepath.Join(asset.DecompressPath(), "tests.sh"))
+		require.Nil(t, err, archive)
+		assert.Equal(t, os.FileMode(0111), info.Mode().Perm()&0111, archive)
+
+		info, err = os.Stat(filepath.Join(asset.DecompressPath(), "assert.sh"))
+		require.Nil(t, err, archive)
+		assert.Equal(t, os.FileMode(0), info.Mode().Perm()&0111, archive)
+
+		packageInstaller, err := NewPackageInstallerFromFileName(filepath.Join(asset.DecompressPath(), DefaultPackageFile))
+		require.Nil(t, err, archive)
+		assert.Equal(t, "assert", packageInstaller.Name)
+	}
+}
+
+type testArchiveEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	body     string
+}
+
+func writeTestTarGz(t *testing.T, dir string, entries []testArchiveEntry) string {
+	archive := filepath.Join(dir, "hostile.tar.gz")
+
+	file, err := os.Create(archive)
+	require.Nil(t, err)
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, entry := range entries {
+		typeflag := entry.typeflag
+		if 0 == typeflag {
+			typeflag = tar.TypeReg
+		}
+
+		require.Nil(t, tarWriter.WriteHeader(&tar.Header{
+			Name:     entry.name,
+			Typeflag: typeflag,
+			Linkname: entry.linkname,
+			Mode:     0644,
+			Size:     int64(len(entry.body)),
+		}))
+
+		_, err = tarWriter.Write([]byte(entry.body))
+		require.Nil(t, err)
+	}
+
+	require.Nil(t, tarWriter.Close())
+	require.Nil(t, gzipWriter.Close())
+
+	return archive
+}
+
+func writeTestZip(t *testing.T, dir string, entries []testArchiveEntry) string {
+	archive := filepath.Join(dir, "hostile.zip")
+
+	file, err := os.Create(archive)
+	require.Nil(t, err)
+	defer file.Close()
+
+	zipWriter := zip.NewWriter(file)
+
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate}
+		header.SetMode(0644)
+
+		if tar.TypeSymlink == entry.typeflag {
+			header.SetMode(os.ModeSymlink | 0777)
+			entry.body = entry.linkname
+		}
+
+		writer, err := zipWriter.CreateHeader(header)
+		require.Nil(t, err)
+
+		_, err = writer.Write([]byte(entry.body))
+		require.Nil(t, err)
+	}
+
+	require.Nil(t, zipWriter.Close())
+
+	return archive
+}
+
+func TestExtractArchiveRejectsHostileEntries(t *testing.T) {
+	cases := map[string]struct {
+		entries  []testArchiveEntry
+		expected error
+		entry    string
+	}{
+		"path traversal": {
+			entries:  []testArchiveEntry{{name: "pkg/../../evil.sh", body: "evil"}},
+			expected: ErrArchivePathTraversal,
+			entry:    "pkg/../../evil.sh",
+		},
+		"absolute path": {
+			entries:  []testArchiveEntry{{name: "/tmp/evil.sh", body: "evil"}},
+			expected: ErrArchiveAbsolutePath,
+			entry:    "/tmp/evil.sh",
+		},
+		"symlink outside root": {
+			entries:  []testArchiveEntry{{name: "pkg/etc", typeflag: tar.TypeSymlink, linkname: "../../etc"}},
+			expected: ErrArchiveLinkOutsideRoot,
+			entry:    "pkg/etc",
+		},
+		"absolute symlink": {
+			entries:  []testArchiveEntry{{name: "pkg/etc", typeflag: tar.TypeSymlink, linkname: "/etc"}},
+			expected: ErrArchiveLinkOutsideRoot,
+			entry:    "pkg/etc",
+		},
+		"symlink chain outside root": {
+			entries: []testArchiveEntry{
+				{name: "d/l", typeflag: tar.TypeSymlink, linkname: ".."},
+				{name: "l2", typeflag: tar.TypeSymlink, linkname: "d/l/../secret"},
+			},
+			expected: ErrArchiveLinkOutsideRoot,
+			entry:    "l2",
+		},
+		"symlink through a later link": {
+			entries: []testArchiveEntry{
+				{name: "l2", typeflag: tar.TypeSymlink, linkname: "d/l/../../evil.sh"},
+				{name: "d/l", typeflag: tar.TypeSymlink, linkname: ".."},
+			},
+			expected: ErrArchiveLinkOutsideRoot,
+			entry:    "l2",
+		},
+		"write through symlink": {
+			entries: []testArchiveEntry{
+				{name: "pkg/link", typeflag: tar.TypeSymlink, linkname: "lib"},
+				{name: "pkg/link/evil.sh", body: "evil"},
+			},
+			expected: ErrArchiveWriteThroughLink,
+			entry:    "pkg/link/evil.sh",
+		},
+	}
+
+	for name, test := range cases {
+		for _, writer := range []func(*testing.T, string, []testArchiveEntry) string{writeTestTarGz, writeTestZip} {
+			tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+			defer os.RemoveAll(tempFolder)
+
+			archive := writer(t, tempFolder, test.entries)
+			target := filepath.Join(tempFolder, "root")
+
+			err := ExtractArchive(archive, target)
+			require.NotNil(t, err, name)
+			assert.True(t, errors.Is(err, test.expected), "%s %s: %s", name, archive, err)
+
+			var entryError *ArchiveEntryError
+			require.True(t, errors.As(err, &entryError), name)
+			assert.Equal(t, test.entry, entryError.Entry, name)
+
+			_, err = os.Stat(filepath.Join(tempFolder, "evil.sh"))
+			assert.True(t, os.IsNotExist(err), name)
+		}
+	}
+}
+
+func TestExtractArchiveHardlinkOutsideRoot(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	archive := writeTestTarGz(t, tempFolder, []testArchiveEntry{
+		{name: "pkg/passwd", typeflag: tar.TypeLink, linkname: "../../etc/passwd"},
+	})
+
+	err := ExtractArchive(archive, filepath.Join(tempFolder, "root"))
+	assert.True(t, errors.Is(err, ErrArchiveLinkOutsideRoot))
+
+	var entryError *ArchiveEntryError
+	require.True(t, errors.As(err, &entryError))
+	assert.Equal(t, "pkg/passwd", entryError.Entry)
+}
+
+func TestExtractArchiveKeepsLinksInsideRoot(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	archive := writeTestTarGz(t, tempFolder, []testArchiveEntry{
+		{name: "pkg/lib/tool.sh", body: "echo tool"},
+		{name: "pkg/tool.sh", typeflag: tar.TypeSymlink, linkname: "lib/tool.sh"},
+		{name: "pkg/copy.sh", typeflag: tar.TypeLink, linkname: "pkg/lib/tool.sh"},
+		{name: "pkg/bin/root", typeflag: tar.TypeSymlink, linkname: "../.."},
+		{name: "pkg/bin/tool.sh", typeflag: tar.TypeSymlink, linkname: "./root/pkg/tool.sh"},
+	})
+
+	target := filepath.Join(tempFolder, "root")
+	require.Nil(t, ExtractArchive(archive, target))
+
+	for _, file := range []string{"pkg/tool.sh", "pkg/copy.sh", "pkg/bin/tool.sh"} {
+		content, err := os.ReadFile(filepath.Join(target, file))
+		require.Nil(t, err, file)
+		assert.Equal(t, "echo tool", string(content), file)
+	}
+}
+
+func TestExtractArchiveLimits(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	entries := []testArchiveEntry{
+		{name: "pkg/a.sh", body: "0123456789"},
+		{name: "pkg/b.sh", body: "0123456789"},
+	}
+
+	for _, writer := range []func(*testing.T, string, []testArchiveEntry) string{writeTestTarGz, writeTestZip} {
+		archive := writer(t, tempFolder, entries)
+
+		err := ExtractArchiveWith(archive, filepath.Join(tempFolder, "size"), ExtractLimits{MaxTotalSize: 15})
+		assert.True(t, errors.Is(err, ErrArchiveTooLarge), archive)
+
+		var entryError *ArchiveEntryError
+		require.True(t, errors.As(err, &entryError), archive)
+		assert.Equal(t, "pkg/b.sh", entryError.Entry)
+
+		err = ExtractArchiveWith(archive, filepath.Join(tempFolder, "entries"), ExtractLimits{MaxEntries: 1})
+		assert.True(t, errors.Is(err, ErrArchiveTooManyEntries), archive)
+
+		require.Nil(t, ExtractArchiveWith(archive, filepath.Join(tempFolder, "ok"), ExtractLimits{MaxTotalSize: 20, MaxEntries: 2}))
+	}
+}
+
+func TestReleaseAssetsWithExtractLimits(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	_, err := NewReleaseAssetsWith(
+		ReleaseAssetsWithSourceTarFile("testdata/sourceTarFile.tar.gz"),
+		ReleaseAssetsWithUntarFilePath(tempFolder),
+		ReleaseAssetsWithExtractLimits(ExtractLimits{MaxEntries: 1}),
+	)
+
+	assert.True(t, errors.Is(err, ErrArchiveTooManyEntries))
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"text/template"
+)
+
+var (
+	ErrInvalidAssetPattern   = errors.New("invalid release asset pattern")
+	ErrReleaseAssetNotFound  = errors.New("release asset not found")
+	ErrAmbiguousReleaseAsset = errors.New("several release assets downloaded")
+)
+
+// AssetPatternData holds the values available to asset patterns and url templates, OS and Arch use the GOOS and
+// GOARCH names and Version is Tag without the leading v
+type AssetPatternData struct {
+	OS           string
+	Arch         string
+	Organization string
+	Name         string
+	Version      string
+	Tag          string
+}
+
+func NewAssetPatternData(releaseVersion *ReleaseVersion) AssetPatternData {
+	return AssetPatternData{
+		OS:           runtime.GOOS,
+		Arch:         runtime.GOARCH,
+		Organization: releaseVersion.Organization,
+		Name:         releaseVersion.Name,
+		Version:      releaseVersion.VersionWithOutV(),
+		Tag:          releaseVersion.Version(),
+	}
+}
+
+// RenderAssetPattern expands a pattern such as mytool_{{.OS}}_{{.Arch}}.tar.gz, the result may still hold glob characters
+func RenderAssetPattern(pattern string, data AssetPatternData) (string, error) {
+	tpl, err := template.New("asset").Option("missingkey=error").Parse(pattern)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidAssetPattern, err)
+	}
+
+	var rendered bytes.Buffer
+	if err = tpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidAssetPattern, err)
+	}
+
+	return rendered.String(), nil
+}
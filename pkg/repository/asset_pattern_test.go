@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"testing"
+)
+
+func TestRenderAssetPattern(t *testing.T) {
+	releaseVersion := NewReleaseVersion("org", "mytool", "v1.2.3")
+
+	rendered, err := RenderAssetPattern("{{.Name}}_{{.Version}}_{{.OS}}_{{.Arch}}.tar.gz", NewAssetPatternData(&releaseVersion))
+	require.Nil(t, err)
+	assert.Equal(t, fmt.Sprintf("mytool_1.2.3_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH), rendered)
+
+	rendered, err = RenderAssetPattern("mytool-{{.Tag}}-*.zip", NewAssetPatternData(&releaseVersion))
+	require.Nil(t, err)
+	assert.Equal(t, "mytool-v1.2.3-*.zip", rendered)
+
+	for _, pattern := range []string{"mytool_{{.OS", "mytool_{{.Platform}}.tar.gz"} {
+		_, err = RenderAssetPattern(pattern, NewAssetPatternData(&releaseVersion))
+		assert.True(t, errors.Is(err, ErrInvalidAssetPattern), pattern)
+	}
+}
+
+func TestDownloadAssetExpectsOneFile(t *testing.T) {
+	releaseVersion := NewReleaseVersion("org", "mytool", "v1.2.3")
+
+	_, err := releaseVersion.DownloadAsset(newMockReleasesProvider(), "")
+	assert.True(t, errors.Is(err, ErrReleaseAssetNotFound))
+
+	_, err = releaseVersion.DownloadAsset(&mockReleasesProvider{DownloadFn: writeMockFiles("a.tar.gz", "b.tar.gz")}, "")
+	assert.True(t, errors.Is(err, ErrAmbiguousReleaseAsset))
+	assert.Contains(t, err.Error(), "a.tar.gz, b.tar.gz")
+}
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	CachePathEnv   = "GO_BPKG_CACHE"
+	cacheEntryFile = "entry.json"
+	cacheTempDir   = ".tmp"
+)
+
+var (
+	ErrCacheEntryNotFound  = errors.New("download cache entry not found")
+	ErrCacheEntryCorrupted = errors.New("download cache entry doesn't match its sha256")
+)
+
+// CacheEntry is a downloaded release file stored under <cache>/<hostname>/<organization>/<name>/<version>
+type CacheEntry struct {
+	Hostname     string    `json:"hostname"`
+	Organization string    `json:"organization"`
+	Name         string    `json:"name"`
+	Version      string    `json:"version"`
+	Provider     string    `json:"provider,omitempty"`
+	Variant      string    `json:"variant,omitempty"`
+	File         string    `json:"file"`
+	Sha256       string    `json:"sha256"`
+	Size         int64     `json:"size"`
+	DownloadedAt time.Time `json:"downloadedAt"`
+	LastUsedAt   time.Time `json:"lastUsedAt"`
+	dir          string
+}
+
+func (entry CacheEntry) Dir() string {
+	return entry.dir
+}
+
+func (entry CacheEntry) FilePath() string {
+	return filepath.Join(entry.dir, entry.File)
+}
+
+func (entry CacheEntry) Key() string {
+	return fmt.Sprintf("%s/%s:%s", entry.Hostname, LockKey(entry.Organization, entry.Name), entry.Version)
+}
+
+// DownloadCache keeps downloaded release files so installing the same version again doesn't fetch it
+type DownloadCache struct {
+	dir string
+}
+
+func NewDownloadCache(dir string) *DownloadCache {
+	return &DownloadCache{dir: dir}
+}
+
+// DefaultCachePath is $GO_BPKG_CACHE when set, otherwise go-bpkg in the user cache dir, $XDG_CACHE_HOME on linux
+func DefaultCachePath() string {
+	if path := os.Getenv(CachePathEnv); "" != path {
+		return path
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), ConfigDirName)
+	}
+
+	return filepath.Join(cacheDir, ConfigDirName)
+}
+
+func (cache *DownloadCache) Dir() string {
+	return cache.dir
+}
+
+// entryDir escapes every component so nested organizations can't collide with other packages
+func (cache *DownloadCache) entryDir(hostname string, releaseVersion *ReleaseVersion) string {
+	return filepath.Join(cache.dir, url.PathEscape(hostname), url.PathEscape(releaseVersion.Organization),
+		url.PathEscape(releaseVersion.Name), url.PathEscape(releaseVersion.Version()))
+}
+
+// Get returns the entry of releaseVersion downloaded from hostname as variant, entries whose file doesn't match its
+// sha256 are removed
+func (cache *DownloadCache) Get(hostname string, variant string, releaseVersion *ReleaseVersion) (CacheEntry, error) {
+	entry, err := readCacheEntry(cache.entryDir(hostname, releaseVersion))
+	if err != nil || variant != entry.Variant {
+		return CacheEntry{}, fmt.Errorf("%w: %s/%s:%s", ErrCacheEntryNotFound, hostname,
+			LockKey(releaseVersion.Organization, releaseVersion.Name), releaseVersion.Version())
+	}
+
+	sha256, err := FileSha256(entry.FilePath())
+	if err != nil || sha256 != entry.Sha256 {
+		_ = cache.Remove(entry)
+
+		return CacheEntry{}, fmt.Errorf("%w: %s", ErrCacheEntryCorrupted, entry.Key())
+	}
+
+	entry.LastUsedAt = time.Now().UTC()
+	_ = writeCacheEntry(entry)
+
+	return entry, nil
+}
+
+// Put copies filePath into the cache, replacing the previous entry of releaseVersion whatever its variant
+func (cache *DownloadCache) Put(hostname string, provider string, variant string, releaseVersion *ReleaseVersion, filePath string) (CacheEntry, error) {
+	tempRoot := filepath.Join(cache.dir, cacheTempDir)
+	if err := os.MkdirAll(tempRoot, 0755); err != nil {
+		return CacheEntry{}, errors.New(fmt.Sprintf("Error can't create cache dir %s", tempRoot))
+	}
+
+	tempDir, err := os.MkdirTemp(tempRoot, "entry")
+	if err != nil {
+		return CacheEntry{}, errors.New(fmt.Sprintf("Error can't create cache dir %s", tempRoot))
+	}
+
+	defer os.RemoveAll(tempDir)
+
+	now := time.Now().UTC()
+	entry := CacheEntry{
+		Hostname:     hostname,
+		Organization: releaseVersion.Organization,
+		Name:         releaseVersion.Name,
+		Version:      releaseVersion.Version(),
+		Provider:     provider,
+		Variant:      variant,
+		File:         filepath.Base(filePath),
+		DownloadedAt: now,
+		LastUsedAt:   now,
+		dir:          tempDir,
+	}
+
+	if entry.Size, err = copyCacheFile(filePath, entry.FilePath()); err != nil {
+		return CacheEntry{}, err
+	}
+
+	if entry.Sha256, err = FileSha256(entry.FilePath()); err != nil {
+		return CacheEntry{}, err
+	}
+
+	if err = writeCacheEntry(entry); err != nil {
+		return CacheEntry{}, err
+	}
+
+	entryDir := cache.entryDir(hostname, releaseVersion)
+	if err = os.MkdirAll(filepath.Dir(entryDir), 0755); err != nil {
+		return CacheEntry{}, errors.New(fmt.Sprintf("Error can't create cache dir %s", filepath.Dir(entryDir)))
+	}
+
+	_ = os.RemoveAll(entryDir)
+	if err = os.Rename(tempDir, entryDir); err != nil {
+		return CacheEntry{}, errors.New(fmt.Sprintf("Error can't move cache entry to %s", entryDir))
+	}
+
+	entry.dir = entryDir
+
+	return entry, nil
+}
+
+// List returns every entry sorted by key, a missing cache dir is an empty cache
+func (cache *DownloadCache) List() ([]CacheEntry, error) {
+	entries := make([]CacheEntry, 0)
+
+	err := filepath.WalkDir(cache.dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) && path == cache.dir {
+			return filepath.SkipDir
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && cacheTempDir == d.Name() {
+			return filepath.SkipDir
+		}
+
+		if d.IsDir() || cacheEntryFile != d.Name() {
+			return nil
+		}
+
+		if entry, err := readCacheEntry(filepath.Dir(path)); err == nil {
+			entries = append(entries, entry)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entries, errors.New(fmt.Sprintf("Error reading cache dir %s", cache.dir))
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key() < entries[j].Key()
+	})
+
+	return entries, nil
+}
+
+// Remove deletes the entry dir and the host, organization and name dirs it leaves empty
+func (cache *DownloadCache) Remove(entry CacheEntry) error {
+	if err := os.RemoveAll(entry.dir); err != nil {
+		return err
+	}
+
+	root := filepath.Clean(cache.dir)
+	for dir := filepath.Dir(entry.dir); strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// Clean removes every entry and the unfinished downloads, the cache dir itself and any file that isn't an entry stay
+// in place since the dir may be shared
+func (cache *DownloadCache) Clean() error {
+	entries, err := cache.List()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err = cache.Remove(entry); err != nil {
+			return err
+		}
+	}
+
+	return os.RemoveAll(filepath.Join(cache.dir, cacheTempDir))
+}
+
+// Prune removes the entries last used before cutoff and returns them
+func (cache *DownloadCache) Prune(cutoff time.Time) ([]CacheEntry, error) {
+	entries, err := cache.List()
+	if err != nil {
+		return nil, err
+	}
+
+	pruned := make([]CacheEntry, 0)
+	for _, entry := range entries {
+		if false == entry.LastUsedAt.Before(cutoff) {
+			continue
+		}
+
+		if err = cache.Remove(entry); err != nil {
+			return pruned, err
+		}
+
+		pruned = append(pruned, entry)
+	}
+
+	return pruned, nil
+}
+
+func readCacheEntry(dir string) (CacheEntry, error) {
+	var entry CacheEntry
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, cacheEntryFile))
+	if err != nil {
+		return entry, err
+	}
+
+	if err = json.Unmarshal(content, &entry); err != nil {
+		return entry, errors.New(fmt.Sprintf("Error unmarsalling %s", filepath.Join(dir, cacheEntryFile)))
+	}
+
+	entry.dir = dir
+
+	return entry, nil
+}
+
+func writeCacheEntry(entry CacheEntry) error {
+	content, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(entry.dir, cacheEntryFile), content, 0644)
+}
+
+func copyCacheFile(source string, destination string) (int64, error) {
+	in, err := os.Open(source)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("Error can't open file %s", source))
+	}
+
+	defer in.Close()
+
+	out, err := os.Create(destination)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("Error Creating file %s", destination))
+	}
+
+	defer out.Close()
+
+	size, err := io.Copy(out, in)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("Error copying file %s to %s", source, destination))
+	}
+
+	return size, nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadCache(t *testing.T) {
+	cacheDir, _ := os.MkdirTemp("", "temp-test-cache-folder")
+	defer os.RemoveAll(cacheDir)
+
+	cache := NewDownloadCache(cacheDir)
+
+	entries, err := NewDownloadCache(filepath.Join(cacheDir, "missing")).List()
+	require.Nil(t, err)
+	assert.Empty(t, entries)
+
+	releaseVersion := NewReleaseVersion("group/sub", "assert.sh", "v1.1")
+
+	_, err = cache.Get("gitlab.com", ArchiveFormatTarGz, &releaseVersion)
+	assert.True(t, errors.Is(err, ErrCacheEntryNotFound))
+
+	entry, err := cache.Put("gitlab.com", GitlabProviderName, ArchiveFormatTarGz, &releaseVersion, "testdata/sourceTarFile.tar.gz")
+	require.Nil(t, err)
+	assert.Equal(t, "gitlab.com/group/sub/assert.sh:v1.1", entry.Key())
+	assert.Equal(t, filepath.Join(cacheDir, "gitlab.com", "group%2Fsub", "assert.sh", "v1.1"), entry.Dir())
+
+	sha256, _ := FileSha256("testdata/sourceTarFile.tar.gz")
+	assert.Equal(t, sha256, entry.Sha256)
+
+	cached, err := cache.Get("gitlab.com", ArchiveFormatTarGz, &releaseVersion)
+	require.Nil(t, err)
+	assert.Equal(t, entry.FilePath(), cached.FilePath())
+	assert.Equal(t, GitlabProviderName, cached.Provider)
+
+	_, err = cache.Get("gitlab.com", "assert_*.tar.gz", &releaseVersion)
+	assert.True(t, errors.Is(err, ErrCacheEntryNotFound))
+
+	_, err = cache.Get("gitlab.corp.example", ArchiveFormatTarGz, &releaseVersion)
+	assert.True(t, errors.Is(err, ErrCacheEntryNotFound))
+
+	require.Nil(t, os.WriteFile(entry.FilePath(), []byte("tampered"), 0644))
+
+	_, err = cache.Get("gitlab.com", ArchiveFormatTarGz, &releaseVersion)
+	assert.True(t, errors.Is(err, ErrCacheEntryCorrupted))
+	assert.NoDirExists(t, entry.Dir())
+}
+
+func TestDownloadCachePruneAndClean(t *testing.T) {
+	cacheDir, _ := os.MkdirTemp("", "temp-test-cache-folder")
+	defer os.RemoveAll(cacheDir)
+
+	cache := NewDownloadCache(cacheDir)
+
+	for _, version := range []string{"v1.0", "v1.1"} {
+		releaseVersion := NewReleaseVersion("rafaelcalleja", "assert.sh", version)
+		_, err := cache.Put(GithubRepository, GithubProviderName, ArchiveFormatTarGz, &releaseVersion, "testdata/sourceTarFile.tar.gz")
+		require.Nil(t, err)
+	}
+
+	entries, err := cache.List()
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "github.com/rafaelcalleja/assert.sh:v1.0", entries[0].Key())
+
+	old := entries[0]
+	old.LastUsedAt = time.Now().Add(-48 * time.Hour)
+	require.Nil(t, writeCacheEntry(old))
+
+	pruned, err := cache.Prune(time.Now().Add(-24 * time.Hour))
+	require.Nil(t, err)
+	require.Len(t, pruned, 1)
+	assert.Equal(t, old.Key(), pruned[0].Key())
+
+	entries, err = cache.List()
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "github.com/rafaelcalleja/assert.sh:v1.1", entries[0].Key())
+
+	unrelated := filepath.Join(cacheDir, "unrelated.txt")
+	require.Nil(t, os.WriteFile(unrelated, []byte("keep"), 0644))
+	require.Nil(t, os.MkdirAll(filepath.Join(cacheDir, cacheTempDir, "download"), 0755))
+
+	require.Nil(t, cache.Clean())
+	assert.FileExists(t, unrelated)
+	assert.NoDirExists(t, filepath.Join(cacheDir, cacheTempDir))
+	assert.NoDirExists(t, filepath.Join(cacheDir, GithubRepository))
+
+	entries, err = cache.List()
+	require.Nil(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDownloadCachedAsset(t *testing.T) {
+	archive, err := os.ReadFile("testdata/sourceTarFile.tar.gz")
+	require.Nil(t, err)
+
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+
+	cacheDir, _ := os.MkdirTemp("", "temp-test-cache-folder")
+	defer os.RemoveAll(cacheDir)
+
+	installDir, _ := os.MkdirTemp("", "temp-test-install-folder")
+	defer os.RemoveAll(installDir)
+
+	cache := NewDownloadCache(cacheDir)
+
+	provider, err := NewHttpProvider(server.URL+"/{{.Name}}-{{.Version}}.tar.gz", "")
+	require.Nil(t, err)
+
+	releaseVersion := NewReleaseVersion("rafaelcalleja", "assert.sh", "v1.1")
+	for i := 0; i < 2; i++ {
+		asset, err := releaseVersion.DownloadCachedAsset(provider, installDir, cache)
+		require.Nil(t, err)
+		require.Nil(t, releaseVersion.InstallAsset(asset, installDir))
+		require.Nil(t, asset.Remove())
+	}
+
+	assert.Equal(t, 1, downloads)
+	assert.True(t, releaseVersion.IsVersionInstalled("v1.1", installDir))
+
+	entries, err := cache.List()
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, HttpProviderName, entries[0].Provider)
+
+	asset, err := releaseVersion.DownloadAsset(provider, installDir)
+	require.Nil(t, err)
+	defer asset.Remove()
+
+	assert.Equal(t, 2, downloads)
+}
+
+func TestDownloadVerifiedAssetBeforeExtracting(t *testing.T) {
+	archive, err := os.ReadFile("testdata/sourceTarFile.tar.gz")
+	require.Nil(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+
+	cacheDir, _ := os.MkdirTemp("", "temp-test-cache-folder")
+	defer os.RemoveAll(cacheDir)
+
+	cache := NewDownloadCache(cacheDir)
+
+	provider, err := NewHttpProvider(server.URL+"/{{.Name}}-{{.Version}}.tar.gz", "")
+	require.Nil(t, err)
+
+	releaseVersion := NewReleaseVersion("rafaelcalleja", "assert.sh", "v1.1")
+
+	var extracted []os.DirEntry
+	downloaded := ""
+	_, err = releaseVersion.DownloadVerifiedAsset(provider, "", cache, func(sourceFile string) error {
+		downloaded = sourceFile
+		extracted, _ = os.ReadDir(filepath.Dir(sourceFile))
+
+		return releaseVersion.VerifyAssetChecksum(provider, sourceFile, "sha256:"+strings.Repeat("00", 32))
+	})
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+
+	require.Len(t, extracted, 1)
+	assert.Equal(t, filepath.Base(downloaded), extracted[0].Name())
+	assert.NoDirExists(t, filepath.Dir(downloaded))
+
+	entries, err := cache.List()
+	require.Nil(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const ChecksumAlgorithmSha256 = "sha256"
+
+var (
+	ErrInvalidChecksum       = errors.New("invalid checksum, format is sha256:<hex digest>")
+	ErrChecksumMismatch      = errors.New("checksum mismatch")
+	ErrChecksumNotListed     = errors.New("asset not listed in published checksums")
+	ErrChecksumsNotPublished = errors.New("release publishes no checksums file")
+
+	// DefaultChecksumFiles are the release asset names, as glob patterns, searched for published checksums
+	DefaultChecksumFiles = []string{"SHA256SUMS", "SHA256SUMS.txt", "sha256sums.txt", "checksums.txt", "*_checksums.txt", "*-checksums.txt", "*_SHA256SUMS"}
+
+	bsdChecksumLineExpression = regexp.MustCompile(`^SHA256 \((.+)\) = ([0-9a-fA-F]{64})$`)
+)
+
+// ChecksumsProvider downloads the checksums files published with a release into downloadDir,
+// ErrChecksumsNotPublished is returned when the release has none
+type ChecksumsProvider interface {
+	DownloadChecksums(releaseVersion *ReleaseVersion, downloadDir string) error
+}
+
+type Checksum struct {
+	Algorithm string
+	Digest    string
+}
+
+func NewChecksum(value string) (Checksum, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), ":", 2)
+	if 2 != len(parts) || ChecksumAlgorithmSha256 != strings.ToLower(parts[0]) || false == isSha256Digest(parts[1]) {
+		return Checksum{}, fmt.Errorf("%w: %s", ErrInvalidChecksum, value)
+	}
+
+	return Checksum{Algorithm: ChecksumAlgorithmSha256, Digest: strings.ToLower(parts[1])}, nil
+}
+
+func (checksum Checksum) String() string {
+	return fmt.Sprintf("%s:%s", checksum.Algorithm, checksum.Digest)
+}
+
+func (checksum Checksum) Verify(filePath string) error {
+	digest, err := FileSha256(filePath)
+	if err != nil {
+		return err
+	}
+
+	if digest != checksum.Digest {
+		return fmt.Errorf("%w: %s expected %s got %s", ErrChecksumMismatch, filepath.Base(filePath), checksum, digest)
+	}
+
+	return nil
+}
+
+// ParseChecksums reads sha256sum output in GNU ("<digest>  name", "<digest> *name") or BSD ("SHA256 (name) = <digest>")
+// format into a file name to digest map, lines with other digests are ignored
+func ParseChecksums(reader io.Reader) (map[string]string, error) {
+	checksums := make(map[string]string)
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if matches := bsdChecksumLineExpression.FindStringSubmatch(line); nil != matches {
+			checksums[checksumFileName(matches[1])] = strings.ToLower(matches[2])
+
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if 2 != len(fields) || false == isSha256Digest(fields[0]) {
+			continue
+		}
+
+		checksums[checksumFileName(strings.TrimPrefix(fields[1], "*"))] = strings.ToLower(fields[0])
+	}
+
+	return checksums, scanner.Err()
+}
+
+func checksumFileName(name string) string {
+	return strings.TrimPrefix(name, "./")
+}
+
+func isSha256Digest(digest string) bool {
+	decoded, err := hex.DecodeString(digest)
+
+	return err == nil && 32 == len(decoded)
+}
+
+// VerifyAssetChecksum checks the downloaded file against expected when given and against the checksums
+// published with the release when the provider can fetch them, it runs before the file is extracted
+func (releaseVersion *ReleaseVersion) VerifyAssetChecksum(provider ReleasesProvider, sourceFile string, expected string) error {
+	if "" != strings.TrimSpace(expected) {
+		checksum, err := NewChecksum(expected)
+		if err != nil {
+			return err
+		}
+
+		if err = checksum.Verify(sourceFile); err != nil {
+			return err
+		}
+	}
+
+	checksumsProvider, ok := provider.(ChecksumsProvider)
+	if false == ok {
+		return nil
+	}
+
+	checksumsDir, err := os.MkdirTemp("", "temp-checksums-folder")
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error Creating temporal dir %s", checksumsDir))
+	}
+
+	defer os.RemoveAll(checksumsDir)
+
+	err = checksumsProvider.DownloadChecksums(releaseVersion, checksumsDir)
+	if errors.Is(err, ErrChecksumsNotPublished) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	published, err := readChecksumsDir(checksumsDir)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(sourceFile)
+	digest, ok := published[name]
+	if false == ok {
+		return fmt.Errorf("%w: %s", ErrChecksumNotListed, name)
+	}
+
+	return Checksum{Algorithm: ChecksumAlgorithmSha256, Digest: digest}.Verify(sourceFile)
+}
+
+func readChecksumsDir(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	published := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		file, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		checksums, err := ParseChecksums(file)
+		_ = file.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		for name, digest := range checksums {
+			published[name] = digest
+		}
+	}
+
+	return published, nil
+}
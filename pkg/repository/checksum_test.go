@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewChecksum(t *testing.T) {
+	digest := strings.Repeat("ab", 32)
+
+	checksum, err := NewChecksum("SHA256:" + strings.ToUpper(digest))
+	require.Nil(t, err)
+	assert.Equal(t, "sha256:"+digest, checksum.String())
+
+	for _, invalid := range []string{digest, "sha256:", "sha256:abc", "md5:" + digest, "sha256:" + strings.Repeat("zz", 32)} {
+		_, err = NewChecksum(invalid)
+		assert.True(t, errors.Is(err, ErrInvalidChecksum), invalid)
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	digestA := strings.Repeat("aa", 32)
+	digestB := strings.Repeat("bb", 32)
+	digestC := strings.Repeat("CC", 32)
+
+	content := fmt.Sprintf("%s  mytool_linux_amd64.tar.gz\n%s *./mytool_darwin_arm64.tar.gz\n\nSHA256 (mytool.zip) = %s\n%s  ignored.tar.gz\n",
+		digestA, digestB, digestC, strings.Repeat("dd", 64))
+
+	checksums, err := ParseChecksums(strings.NewReader(content))
+	require.Nil(t, err)
+
+	assert.Equal(t, map[string]string{
+		"mytool_linux_amd64.tar.gz":  digestA,
+		"mytool_darwin_arm64.tar.gz": digestB,
+		"mytool.zip":                 strings.ToLower(digestC),
+	}, checksums)
+}
+
+func TestVerifyAssetChecksum(t *testing.T) {
+	asset := "testdata/sourceTarFile.tar.gz"
+	digest, err := FileSha256(asset)
+	require.Nil(t, err)
+
+	releaseVersion := NewReleaseVersion("org", "assert.sh", "v1.1")
+	provider := newMockReleasesProvider()
+	expected := "sha256:" + digest
+
+	assert.Nil(t, releaseVersion.VerifyAssetChecksum(provider, asset, expected))
+	assert.Nil(t, releaseVersion.VerifyAssetChecksum(provider, asset, ""))
+
+	err = releaseVersion.VerifyAssetChecksum(provider, asset, "sha256:"+strings.Repeat("00", 32))
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+
+	published := func(content string) *mockChecksumsReleasesProvider {
+		return &mockChecksumsReleasesProvider{
+			mockReleasesProvider: provider,
+			DownloadChecksumsFn: func(releaseVersion *ReleaseVersion, downloadDir string) error {
+				return os.WriteFile(filepath.Join(downloadDir, "SHA256SUMS"), []byte(content), 0644)
+			},
+		}
+	}
+
+	assert.Nil(t, releaseVersion.VerifyAssetChecksum(published(fmt.Sprintf("%s  sourceTarFile.tar.gz\n", digest)), asset, ""))
+
+	err = releaseVersion.VerifyAssetChecksum(published(fmt.Sprintf("%s  sourceTarFile.tar.gz\n", strings.Repeat("00", 32))), asset, "")
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+
+	err = releaseVersion.VerifyAssetChecksum(published(fmt.Sprintf("%s  other.tar.gz\n", digest)), asset, "")
+	assert.True(t, errors.Is(err, ErrChecksumNotListed))
+
+	notPublished := &mockChecksumsReleasesProvider{
+		mockReleasesProvider: provider,
+		DownloadChecksumsFn: func(releaseVersion *ReleaseVersion, downloadDir string) error {
+			return ErrChecksumsNotPublished
+		},
+	}
+
+	assert.Nil(t, releaseVersion.VerifyAssetChecksum(notPublished, asset, ""))
+}
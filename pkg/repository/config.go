@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	ConfigDirName     = "go-bpkg"
+	DefaultConfigFile = "config.json"
+	ConfigPathEnv     = "GO_BPKG_CONFIG"
+)
+
+// Config TrustedKeys maps an organization to its publisher public key files, relative paths are resolved
+// from the directory of the config file. UnsignedOrganizations opts organizations without trusted keys out of
+// signature verification. Both name organizations of forges on a non default host as host/organization, e.g.
+// ghe.corp.example/org. HttpSources maps an organization to the artifact server its packages are downloaded from
+type Config struct {
+	TrustedKeys           map[string][]string   `json:"trustedKeys,omitempty"`
+	UnsignedOrganizations []string              `json:"unsignedOrganizations,omitempty"`
+	HttpSources           map[string]HttpSource `json:"httpSources,omitempty"`
+	path                  string
+}
+
+type HttpSource struct {
+	URLTemplate string `json:"urlTemplate"`
+	IndexURL    string `json:"indexUrl,omitempty"`
+}
+
+// DefaultConfigPath is $GO_BPKG_CONFIG when set, otherwise config.json in the go-bpkg user config dir
+func DefaultConfigPath() string {
+	if path := os.Getenv(ConfigPathEnv); "" != path {
+		return path
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join(ConfigDirName, DefaultConfigFile)
+	}
+
+	return filepath.Join(configDir, ConfigDirName, DefaultConfigFile)
+}
+
+// NewConfigFromFileName returns an empty config when filePath doesn't exist
+func NewConfigFromFileName(filePath string) (*Config, error) {
+	config := &Config{path: filePath}
+
+	file, err := ioutil.ReadFile(filePath)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Error can't open file %s", filePath))
+	}
+
+	if err = json.Unmarshal(file, config); err != nil {
+		return nil, errors.New(fmt.Sprintf("Error unmarsalling %s", filePath))
+	}
+
+	return config, nil
+}
+
+// HttpSource returns the artifact server configured for organization
+func (config *Config) HttpSource(organization string) (HttpSource, bool) {
+	source, ok := config.HttpSources[organization]
+
+	return source, ok && "" != source.URLTemplate
+}
+
+func (config *Config) Path() string {
+	return config.path
+}
+
+func (config *Config) resolvePath(path string) string {
+	if filepath.IsAbs(path) || "" == config.path {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(config.path), path)
+}
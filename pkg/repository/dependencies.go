@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+var (
+	DefaultDependencyFile = "package.json"
+)
+
+// DependencyFile Assets maps "org/name" to the release asset pattern installed instead of the source archive
+type DependencyFile struct {
+	Dependencies map[string]string `json:"dependencies,omitempty"`
+	Assets       map[string]string `json:"assets,omitempty"`
+}
+
+func NewDependencyFileFromFileName(filePath string) (*DependencyFile, error) {
+	file, err := ioutil.ReadFile(filePath)
+
+	if err != nil {
+		return &DependencyFile{}, errors.New(fmt.Sprintf("Error can't open file %s", filePath))
+	}
+
+	data := new(DependencyFile)
+
+	if err = json.Unmarshal(file, data); err != nil {
+		return &DependencyFile{}, errors.New(fmt.Sprintf("Error unmarsalling %s", filePath))
+	}
+
+	return data, nil
+}
+
+func (dependencyFile *DependencyFile) Packages() ([]FullyQualifyPackage, error) {
+	return dependencyPackages(dependencyFile.Dependencies)
+}
+
+// AssetPattern returns the release asset pattern configured for the package, "" downloads the source archive
+func (dependencyFile *DependencyFile) AssetPattern(organization string, name string) string {
+	return dependencyFile.Assets[LockKey(organization, name)]
+}
+
+// dependencyPackages converts a bpkg style "org/name": "version" map into packages sorted by name
+func dependencyPackages(dependencies map[string]string) ([]FullyQualifyPackage, error) {
+	names := make([]string, 0, len(dependencies))
+	for name := range dependencies {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	packages := make([]FullyQualifyPackage, 0, len(names))
+	for _, name := range names {
+		version := strings.TrimSpace(dependencies[name])
+		if "" == version || "*" == version {
+			version = "latest"
+		}
+
+		fqp, err := NewFullyQualifyPackage(fmt.Sprintf("%s:%s", name, version))
+		if err != nil {
+			return []FullyQualifyPackage{}, fmt.Errorf("%w: dependency %s:%s", err, name, version)
+		}
+
+		packages = append(packages, fqp)
+	}
+
+	return packages, nil
+}
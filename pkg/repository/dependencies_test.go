@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDependencyFile(t *testing.T) {
+	dependencyFile, err := NewDependencyFileFromFileName("testdata/dependencies.json")
+	require.Nil(t, err)
+
+	packages, err := dependencyFile.Packages()
+	require.Nil(t, err)
+
+	var specs []string
+	for _, fqp := range packages {
+		specs = append(specs, fqp.String())
+	}
+
+	assert.Equal(t, []string{"bpkg/echo-eval:latest", "bpkg/term:0.0.1", "rafaelcalleja/assert.sh:v1.1"}, specs)
+
+	assert.Equal(t, "term_{{.OS}}_{{.Arch}}.tar.gz", dependencyFile.AssetPattern("bpkg", "term"))
+	assert.Equal(t, "", dependencyFile.AssetPattern("bpkg", "echo-eval"))
+
+	_, err = NewDependencyFileFromFileName("testdata/not_found.json")
+	assert.NotNil(t, err)
+
+	_, err = NewDependencyFileFromFileName("testdata/invalid.json")
+	assert.NotNil(t, err)
+
+	invalid := DependencyFile{Dependencies: map[string]string{"organization//name": "v1.0"}}
+	_, err = invalid.Packages()
+	assert.True(t, errors.Is(err, ErrFullyQualifyPackageInvalidFormat))
+
+	empty := DependencyFile{}
+	packages, err = empty.Packages()
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(packages))
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrDependencyCycle    = errors.New("dependency cycle detected")
+	ErrDependencyConflict = errors.New("dependency version conflict")
+)
+
+// DependencyFetchFn resolves fqp to a version and reads its manifest without installing it, parent is the package
+// declaring fqp as a dependency, nil for the root package
+type DependencyFetchFn func(fqp FullyQualifyPackage, parent *DependencyFetch) (*DependencyFetch, error)
+
+// DependencyFetch is a package resolved to a version with its manifest. Install runs once the whole tree resolved,
+// installed is false when the package was already present, Discard runs when it won't be installed. Key is the lock
+// key of the package, with its host and alias, the spec key is used when empty. Scheme, Hostname and Remote are the
+// provider the package came from, dependencies without a provider of their own are fetched from it
+type DependencyFetch struct {
+	Package  FullyQualifyPackage
+	Metadata *PackageInstaller
+	Install  func() (installed bool, err error)
+	Discard  func()
+	Key      string
+	Scheme   string
+	Hostname string
+	Remote   string
+}
+
+// DependencyNode Package is the package at its resolved version, duplicates included
+type DependencyNode struct {
+	Package      FullyQualifyPackage
+	Installed    bool
+	Duplicate    bool
+	Dependencies []*DependencyNode
+	key          string
+	fetch        *DependencyFetch
+}
+
+type DependencyResolver struct {
+	fetchFn  DependencyFetchFn
+	resolved map[string]*DependencyNode
+	path     []string
+}
+
+func NewDependencyResolver(fetchFn DependencyFetchFn) *DependencyResolver {
+	return &DependencyResolver{
+		fetchFn:  fetchFn,
+		resolved: make(map[string]*DependencyNode),
+		path:     make([]string, 0),
+	}
+}
+
+// Resolve fetches fqp and, depth first, every dependency declared in its manifest, then installs the whole tree.
+// Packages are deduplicated by their lock key across every call on the same resolver, so the same organization/name
+// on two hosts are two packages, a later spec the resolved
+// version doesn't satisfy and cycles fail before anything is installed
+func (resolver *DependencyResolver) Resolve(fqp FullyQualifyPackage) (*DependencyNode, error) {
+	pending := make([]*DependencyNode, 0)
+
+	tree, err := resolver.resolve(fqp, nil, &pending)
+	if err != nil {
+		resolver.discard(pending)
+
+		return nil, err
+	}
+
+	for i, node := range pending {
+		if node.Installed, err = node.fetch.Install(); err != nil {
+			resolver.discard(pending[i:])
+
+			return nil, err
+		}
+	}
+
+	return tree, nil
+}
+
+// discard forgets the packages that won't be installed so later calls fetch them again
+func (resolver *DependencyResolver) discard(nodes []*DependencyNode) {
+	for _, node := range nodes {
+		delete(resolver.resolved, node.key)
+
+		if nil != node.fetch.Discard {
+			node.fetch.Discard()
+		}
+	}
+}
+
+func (resolver *DependencyResolver) resolve(fqp FullyQualifyPackage, parent *DependencyFetch, pending *[]*DependencyNode) (*DependencyNode, error) {
+	key := dependencyKey(fqp, parent)
+
+	for _, name := range resolver.path {
+		if name == key {
+			return nil, fmt.Errorf("%w: %s -> %s", ErrDependencyCycle, strings.Join(resolver.path, " -> "), key)
+		}
+	}
+
+	if resolved, ok := resolver.resolved[key]; ok {
+		return resolver.duplicate(fqp, resolved)
+	}
+
+	resolver.path = append(resolver.path, key)
+	defer func() {
+		resolver.path = resolver.path[:len(resolver.path)-1]
+	}()
+
+	fetch, err := resolver.fetchFn(fqp, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	// the provider may place the package on another host than the spec tells, or install it under an alias
+	if "" != fetch.Key && key != fetch.Key {
+		key = fetch.Key
+		resolver.path[len(resolver.path)-1] = key
+
+		if resolved, ok := resolver.resolved[key]; ok {
+			if nil != fetch.Discard {
+				fetch.Discard()
+			}
+
+			return resolver.duplicate(fqp, resolved)
+		}
+	}
+
+	node := &DependencyNode{
+		Package:      fetch.Package,
+		Dependencies: make([]*DependencyNode, 0),
+		key:          key,
+		fetch:        fetch,
+	}
+
+	resolver.resolved[key] = node
+	*pending = append(*pending, node)
+
+	dependencies, err := fetch.Metadata.DependencyPackages()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	for _, dependency := range dependencies {
+		child, err := resolver.resolve(dependency, fetch, pending)
+		if err != nil {
+			return nil, err
+		}
+
+		node.Dependencies = append(node.Dependencies, child)
+	}
+
+	return node, nil
+}
+
+// duplicate is the node of a package resolved before, fqp has to be satisfied by its version
+func (resolver *DependencyResolver) duplicate(fqp FullyQualifyPackage, resolved *DependencyNode) (*DependencyNode, error) {
+	if false == versionSatisfies(fqp.Version(), resolved.Package.Version()) {
+		return nil, fmt.Errorf("%w: %s requires %s but %s is resolved", ErrDependencyConflict,
+			strings.Join(resolver.path, " -> "), fqp.String(), resolved.Package.String())
+	}
+
+	return &DependencyNode{Package: resolved.Package, Duplicate: true, key: resolved.key}, nil
+}
+
+// dependencyKey is the lock key fqp is expected at, specs without a scheme are fetched from the provider and host of
+// the package declaring them
+func dependencyKey(fqp FullyQualifyPackage, parent *DependencyFetch) string {
+	scheme, hostname := fqp.Scheme(), fqp.Hostname()
+	if "" == scheme && nil != parent && "" != parent.Scheme {
+		scheme = parent.Scheme
+		if "" == hostname {
+			hostname = parent.Hostname
+		}
+	}
+
+	return LockKey(PackageOrganization(scheme, hostname, fqp.Organization()), fqp.Name())
+}
+
+// versionSatisfies reports whether the version resolved for a package satisfies another spec of it, latest accepts
+// any version and exact versions compare semantically when both are semantic versions
+func versionSatisfies(requested string, resolved string) bool {
+	if "" == requested || "latest" == requested || requested == resolved {
+		return true
+	}
+
+	version, err := NewSemanticVersion(resolved)
+	if err != nil {
+		return false
+	}
+
+	if IsVersionConstraint(requested) {
+		constraint, err := NewVersionConstraint(requested)
+
+		return err == nil && constraint.Matches(version)
+	}
+
+	other, err := NewSemanticVersion(requested)
+
+	return err == nil && 0 == version.Compare(other)
+}
+
+// Walk visits every node of the tree once, duplicates included
+func (node *DependencyNode) Walk(fn func(node *DependencyNode)) {
+	fn(node)
+
+	for _, child := range node.Dependencies {
+		child.Walk(fn)
+	}
+}
+
+func (node *DependencyNode) String() string {
+	builder := new(strings.Builder)
+	builder.WriteString(node.label())
+	builder.WriteString("\n")
+	node.writeChildren(builder, "")
+
+	return builder.String()
+}
+
+func (node *DependencyNode) label() string {
+	if node.Duplicate {
+		return fmt.Sprintf("%s (deduped)", node.Package.String())
+	}
+
+	return node.Package.String()
+}
+
+func (node *DependencyNode) writeChildren(builder *strings.Builder, prefix string) {
+	for i, child := range node.Dependencies {
+		branch, indent := "├── ", "│   "
+		if i == len(node.Dependencies)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		builder.WriteString(prefix + branch + child.label() + "\n")
+		child.writeChildren(builder, prefix+indent)
+	}
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// newDependencyFetchFnMock resolves latest to the latest entry of the package and constraints to versions, installs and discards are recorded
+// once the resolver runs them
+func newDependencyFetchFnMock(manifests map[string]map[string]string, installs *[]string, discards *[]string) DependencyFetchFn {
+	latest := map[string]string{"org/c": "v2.0"}
+	versions := []string{"v1.0", "v2.0", "v2.1"}
+
+	return func(fqp FullyQualifyPackage, parent *DependencyFetch) (*DependencyFetch, error) {
+		key := LockKey(fqp.Organization(), fqp.Name())
+		dependencies, ok := manifests[key]
+		if !ok {
+			return nil, errors.New("package not found " + key)
+		}
+
+		if "latest" == fqp.Version() {
+			fqp = fqp.CopyWithVersion(latest[key])
+		}
+
+		if fqp.IsVersionConstraint() {
+			constraint, _ := NewVersionConstraint(fqp.Version())
+			version, _ := constraint.Highest(versions)
+			fqp = fqp.CopyWithVersion(version)
+		}
+
+		return &DependencyFetch{
+			Package:  fqp,
+			Metadata: &PackageInstaller{Name: key, Dependencies: dependencies},
+			Install: func() (bool, error) {
+				*installs = append(*installs, fqp.String())
+
+				return true, nil
+			},
+			Discard: func() {
+				*discards = append(*discards, fqp.String())
+			},
+		}, nil
+	}
+}
+
+func TestDependencyResolverTree(t *testing.T) {
+	var installs, discards []string
+
+	resolver := NewDependencyResolver(newDependencyFetchFnMock(map[string]map[string]string{
+		"org/a": {"org/b": "v1.0", "org/c": "v2.0"},
+		"org/b": {"org/c": "v2.0"},
+		"org/c": {},
+		"org/d": {"org/c": "latest"},
+	}, &installs, &discards))
+
+	root, err := NewFullyQualifyPackage("org/a:v1.0")
+	require.Nil(t, err)
+
+	tree, err := resolver.Resolve(root)
+	require.Nil(t, err)
+
+	assert.Equal(t, []string{"org/a:v1.0", "org/b:v1.0", "org/c:v2.0"}, installs)
+	assert.Equal(t, "org/a:v1.0\n├── org/b:v1.0\n│   └── org/c:v2.0\n└── org/c:v2.0 (deduped)\n", tree.String())
+
+	var visited []string
+	tree.Walk(func(node *DependencyNode) {
+		if node.Installed {
+			visited = append(visited, node.Package.String())
+		}
+	})
+	assert.Equal(t, installs, visited)
+
+	other, err := NewFullyQualifyPackage("org/d:v1.0")
+	require.Nil(t, err)
+
+	tree, err = resolver.Resolve(other)
+	require.Nil(t, err)
+
+	assert.Equal(t, []string{"org/a:v1.0", "org/b:v1.0", "org/c:v2.0", "org/d:v1.0"}, installs)
+	assert.Equal(t, "org/d:v1.0\n└── org/c:v2.0 (deduped)\n", tree.String())
+	assert.Empty(t, discards)
+}
+
+func TestDependencyResolverConflict(t *testing.T) {
+	var installs, discards []string
+
+	resolver := NewDependencyResolver(newDependencyFetchFnMock(map[string]map[string]string{
+		"org/a": {"org/b": "v1.0", "org/c": "v2.0"},
+		"org/b": {"org/c": "v1.0"},
+		"org/c": {},
+		"org/d": {"org/c": "^2.0", "org/e": "v1.0", "org/f": "v1.0"},
+		"org/e": {"org/c": "v2.1"},
+		"org/f": {"org/c": "2.1.0"},
+	}, &installs, &discards))
+
+	root, err := NewFullyQualifyPackage("org/a:v1.0")
+	require.Nil(t, err)
+
+	_, err = resolver.Resolve(root)
+	assert.True(t, errors.Is(err, ErrDependencyConflict))
+	assert.Contains(t, err.Error(), "org/a requires org/c:v2.0 but org/c:v1.0 is resolved")
+	assert.Empty(t, installs)
+	assert.Equal(t, []string{"org/a:v1.0", "org/b:v1.0", "org/c:v1.0"}, discards)
+
+	root, err = NewFullyQualifyPackage("org/d:v1.0")
+	require.Nil(t, err)
+
+	tree, err := resolver.Resolve(root)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"org/d:v1.0", "org/c:v2.1", "org/e:v1.0", "org/f:v1.0"}, installs)
+	assert.Equal(t, "org/d:v1.0\n├── org/c:v2.1\n├── org/e:v1.0\n│   └── org/c:v2.1 (deduped)\n└── org/f:v1.0\n    └── org/c:v2.1 (deduped)\n", tree.String())
+}
+
+func TestDependencyResolverCycle(t *testing.T) {
+	var installs, discards []string
+
+	resolver := NewDependencyResolver(newDependencyFetchFnMock(map[string]map[string]string{
+		"org/a": {"org/b": "v1.0"},
+		"org/b": {"org/c": "v1.0"},
+		"org/c": {"org/a": "v1.0"},
+	}, &installs, &discards))
+
+	root, err := NewFullyQualifyPackage("org/a:v1.0")
+	require.Nil(t, err)
+
+	_, err = resolver.Resolve(root)
+	assert.True(t, errors.Is(err, ErrDependencyCycle))
+	assert.Contains(t, err.Error(), "org/a -> org/b -> org/c -> org/a")
+	assert.Empty(t, installs)
+	assert.Equal(t, []string{"org/a:v1.0", "org/b:v1.0", "org/c:v1.0"}, discards)
+}
+
+func TestDependencyResolverErrors(t *testing.T) {
+	var installs, discards []string
+
+	resolver := NewDependencyResolver(newDependencyFetchFnMock(map[string]map[string]string{
+		"org/a": {"org/missing": "v1.0"},
+		"org/b": {"org//invalid": "v1.0"},
+	}, &installs, &discards))
+
+	root, err := NewFullyQualifyPackage("org/a:v1.0")
+	require.Nil(t, err)
+
+	_, err = resolver.Resolve(root)
+	assert.Contains(t, err.Error(), "org/missing")
+
+	root, err = NewFullyQualifyPackage("org/b:v1.0")
+	require.Nil(t, err)
+
+	_, err = resolver.Resolve(root)
+	assert.True(t, errors.Is(err, ErrFullyQualifyPackageInvalidFormat))
+}
+
+func TestManifestDependencies(t *testing.T) {
+	packageFile, err := NewPackageInstallerFromFileName("testdata/dependencies.json")
+	require.Nil(t, err)
+
+	packages, err := packageFile.DependencyPackages()
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(packages))
+}
+
+func TestDependencyResolverParent(t *testing.T) {
+	manifests := map[string]map[string]string{
+		"org/a": {"org/b": "v1.0"},
+		"org/b": {"other:org/c": "v1.0"},
+		"org/c": {},
+	}
+
+	parents := make(map[string]string)
+	resolver := NewDependencyResolver(func(fqp FullyQualifyPackage, parent *DependencyFetch) (*DependencyFetch, error) {
+		scheme := fqp.Scheme()
+		if "" == scheme && nil != parent {
+			scheme = parent.Scheme
+		}
+
+		parents[fqp.Name()] = ""
+		if nil != parent {
+			parents[fqp.Name()] = parent.Package.String()
+		}
+
+		return &DependencyFetch{
+			Package:  fqp,
+			Metadata: &PackageInstaller{Dependencies: manifests[LockKey(fqp.Organization(), fqp.Name())]},
+			Install: func() (bool, error) {
+				return true, nil
+			},
+			Scheme: scheme,
+		}, nil
+	})
+
+	root, err := NewFullyQualifyPackage("gitlab:org/a:v1.0")
+	require.Nil(t, err)
+
+	tree, err := resolver.Resolve(root)
+	require.Nil(t, err)
+
+	assert.Equal(t, map[string]string{"a": "", "b": "gitlab:org/a:v1.0", "c": "org/b:v1.0"}, parents)
+	assert.Equal(t, "gitlab", tree.Dependencies[0].fetch.Scheme)
+	assert.Equal(t, "other", tree.Dependencies[0].Dependencies[0].fetch.Scheme)
+}
+
+func TestDependencyResolverHostKeys(t *testing.T) {
+	manifests := map[string]map[string]string{
+		"org/a": {"org/b": "v1.0", "gitlab:org/b": "v1.0"},
+		"org/b": {},
+	}
+
+	var fetches []string
+	failInstall := true
+	resolver := NewDependencyResolver(func(fqp FullyQualifyPackage, parent *DependencyFetch) (*DependencyFetch, error) {
+		scheme, hostname := fqp.Scheme(), fqp.Hostname()
+		if "" == scheme && nil != parent {
+			scheme, hostname = parent.Scheme, parent.Hostname
+		}
+
+		dependencies := manifests[LockKey(fqp.Organization(), fqp.Name())]
+		if nil == parent {
+			fqp = fqp.CopyWithName("alias")
+		}
+
+		key := LockKey(PackageOrganization(scheme, hostname, fqp.Organization()), fqp.Name())
+		fetches = append(fetches, key)
+
+		return &DependencyFetch{
+			Package:  fqp,
+			Metadata: &PackageInstaller{Dependencies: dependencies},
+			Install: func() (bool, error) {
+				if failInstall && "alias" == fqp.Name() {
+					return false, errors.New("install failed")
+				}
+
+				return true, nil
+			},
+			Key:      key,
+			Scheme:   scheme,
+			Hostname: hostname,
+		}, nil
+	})
+
+	root, err := NewFullyQualifyPackage("gitlab://gitlab.corp.example/org/a:v1.0")
+	require.Nil(t, err)
+
+	_, err = resolver.Resolve(root)
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"gitlab.corp.example/org/alias", "org/b", "gitlab.corp.example/org/b"}, fetches)
+
+	failInstall = false
+	fetches = nil
+
+	tree, err := resolver.Resolve(root)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"gitlab.corp.example/org/alias", "org/b", "gitlab.corp.example/org/b"}, fetches)
+	assert.Equal(t, 2, len(tree.Dependencies))
+	assert.False(t, tree.Dependencies[0].Duplicate)
+	assert.False(t, tree.Dependencies[1].Duplicate)
+}
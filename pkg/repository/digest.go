@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+func FileSha256(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if nil != err {
+		return "", errors.New(fmt.Sprintf("Error can't open file %s", filePath))
+	}
+
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); nil != err {
+		return "", errors.New(fmt.Sprintf("Error reading file %s", filePath))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// forgeClient is the REST API client shared by the gitlab and gitea providers, the token is sent in tokenHeader
+// formatted with tokenFormat and only to the API host
+type forgeClient struct {
+	client      *http.Client
+	baseURL     string
+	token       string
+	tokenHeader string
+	tokenFormat string
+}
+
+// forgeReleaseFile is a file uploaded to a release, a gitlab release link or a gitea attachment
+type forgeReleaseFile struct {
+	Name string
+	URL  string
+}
+
+// forgeReleaseFilesFn lists the files uploaded to a release
+type forgeReleaseFilesFn func(releaseVersion *ReleaseVersion) ([]forgeReleaseFile, error)
+
+// header only carries the token to the API host, release files may be served from elsewhere
+func (f forgeClient) header(fileURL string) http.Header {
+	header := http.Header{}
+	if "" == f.token {
+		return header
+	}
+
+	base, err := url.Parse(f.baseURL)
+	if err != nil {
+		return header
+	}
+
+	target, err := url.Parse(fileURL)
+	if err != nil || target.Host != base.Host {
+		return header
+	}
+
+	header.Set(f.tokenHeader, fmt.Sprintf(f.tokenFormat, f.token))
+
+	return header
+}
+
+// getJSON decodes the answer of fileURL into data, the response header is returned for paginated endpoints
+func (f forgeClient) getJSON(fileURL string, data interface{}) (http.Header, error) {
+	response, err := httpGet(f.client, fileURL, f.header(fileURL))
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if err = json.NewDecoder(response.Body).Decode(data); err != nil {
+		return nil, errors.New(fmt.Sprintf("Error unmarshalling %s", fileURL))
+	}
+
+	return response.Header, nil
+}
+
+func (f forgeClient) download(fileURL string, filePath string) error {
+	return httpDownload(f.client, fileURL, f.header(fileURL), filePath)
+}
+
+func (f forgeClient) downloadFile(file forgeReleaseFile, downloadDir string) error {
+	return f.download(file.URL, filepath.Join(downloadDir, filepath.Base(filepath.FromSlash(file.Name))))
+}
+
+// downloadAsset downloads the only release file matching the asset pattern rendered for releaseVersion
+func (f forgeClient) downloadAsset(releaseVersion *ReleaseVersion, asset string, files forgeReleaseFilesFn, downloadDir string) error {
+	pattern, err := RenderAssetPattern(asset, NewAssetPatternData(releaseVersion))
+	if err != nil {
+		return err
+	}
+
+	matches, err := matchReleaseFiles(releaseVersion, files, []string{pattern})
+	if err != nil {
+		return err
+	}
+
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("%w: %s", ErrReleaseAssetNotFound, pattern)
+	case 1:
+		return f.downloadFile(matches[0], downloadDir)
+	default:
+		names := make([]string, 0, len(matches))
+		for _, match := range matches {
+			names = append(names, match.Name)
+		}
+
+		return fmt.Errorf("%w: %s", ErrAmbiguousReleaseAsset, strings.Join(names, ", "))
+	}
+}
+
+// downloadChecksums downloads every release file named like DefaultChecksumFiles
+func (f forgeClient) downloadChecksums(releaseVersion *ReleaseVersion, files forgeReleaseFilesFn, downloadDir string) error {
+	matches, err := matchReleaseFiles(releaseVersion, files, DefaultChecksumFiles)
+	if err != nil {
+		return err
+	}
+
+	if 0 == len(matches) {
+		return ErrChecksumsNotPublished
+	}
+
+	for _, match := range matches {
+		if err = f.downloadFile(match, downloadDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f forgeClient) downloadSignature(releaseVersion *ReleaseVersion, assetName string, files forgeReleaseFilesFn, downloadDir string) error {
+	matches, err := matchReleaseFiles(releaseVersion, files, []string{assetName + SignatureExtension})
+	if err != nil {
+		return err
+	}
+
+	if 0 == len(matches) {
+		return fmt.Errorf("%w: %s%s", ErrSignatureNotFound, assetName, SignatureExtension)
+	}
+
+	return f.downloadFile(matches[0], downloadDir)
+}
+
+// matchReleaseFiles returns the release files whose name matches any of patterns
+func matchReleaseFiles(releaseVersion *ReleaseVersion, files forgeReleaseFilesFn, patterns []string) ([]forgeReleaseFile, error) {
+	released, err := files(releaseVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]forgeReleaseFile, 0)
+	for _, file := range released {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, file.Name); matched {
+				matches = append(matches, file)
+
+				break
+			}
+		}
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testForgeArchive as a route body serves testdata/sourceTarFile.tar.gz
+const testForgeArchive = "@archive"
+
+type testForgeRoute struct {
+	body     string
+	nextPage string
+}
+
+// newTestForgeServer serves routes keyed by escaped path, "?" and the page query, {{.URL}} in a body is the server
+// url. Requests without the token in tokenHeader formatted with tokenFormat are unauthorized
+func newTestForgeServer(t *testing.T, tokenHeader string, tokenFormat string, token string, routes map[string]testForgeRoute) *httptest.Server {
+	archive, err := os.ReadFile("testdata/sourceTarFile.tar.gz")
+	require.Nil(t, err)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Replace(tokenFormat, "%s", token, 1) != r.Header.Get(tokenHeader) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		route, ok := routes[r.URL.EscapedPath()+"?"+r.URL.Query().Get("page")]
+		if false == ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if "" != route.nextPage {
+			w.Header().Set(gitlabNextPageHeader, route.nextPage)
+		}
+
+		if testForgeArchive == route.body {
+			_, _ = w.Write(archive)
+			return
+		}
+
+		_, _ = w.Write([]byte(strings.ReplaceAll(route.body, "{{.URL}}", server.URL)))
+	}))
+
+	return server
+}
+
+func TestForgeClientHeader(t *testing.T) {
+	forge := forgeClient{baseURL: "https://gitea.corp.example/api/v1", token: "secret", tokenHeader: "Authorization", tokenFormat: "token %s"}
+
+	assert.Equal(t, "token secret", forge.header("https://gitea.corp.example/api/v1/repos/org/name").Get("Authorization"))
+	assert.Empty(t, forge.header("https://cdn.example/attachments/1").Get("Authorization"))
+
+	forge.token = ""
+	assert.Empty(t, forge.header("https://gitea.corp.example/api/v1/repos/org/name").Get("Authorization"))
+}
+
+func TestForgeProviderListLimitAndToken(t *testing.T) {
+	gitlab := newTestGitlabServer(t, "secret")
+	defer gitlab.Close()
+
+	gitea := newTestGiteaServer(t, "secret")
+	defer gitea.Close()
+
+	for name, newFinder := range map[string]func(token string, limit int) (ReleaseVersionFinder, error){
+		GitlabProviderName: func(token string, limit int) (ReleaseVersionFinder, error) {
+			return NewGitlabProviderWith(GitlabWithBaseURL(gitlab.URL+"/api/v4"), GitlabWithToken(token), GitlabWithListLimit(limit))
+		},
+		GiteaProviderName: func(token string, limit int) (ReleaseVersionFinder, error) {
+			return NewGiteaProviderWith(GiteaWithBaseURL(gitea.URL+"/api/v1"), GiteaWithToken(token), GiteaWithListLimit(limit))
+		},
+	} {
+		organization := "rafaelcalleja"
+		if GitlabProviderName == name {
+			organization = "group/sub"
+		}
+
+		limited, err := newFinder("secret", 1)
+		require.Nil(t, err, name)
+
+		versions, err := limited.List(organization, "assert.sh")
+		require.Nil(t, err, name)
+		assert.Len(t, versions, 1, name)
+
+		unauthorized, err := newFinder("", 1)
+		require.Nil(t, err, name)
+
+		_, err = unauthorized.Latest(organization, "assert.sh")
+
+		var statusError *HttpStatusError
+		require.True(t, errors.As(err, &statusError), name)
+		assert.Equal(t, http.StatusUnauthorized, statusError.StatusCode, name)
+	}
+}
@@ -9,44 +9,138 @@ import (
 
 var (
 	ErrFullyQualifyPackageInvalidFormat = errors.New("fully qualify package invalid format")
+
+	// specs may start with a provider scheme and a hostname, the organization may be a nested group path, versions
+	// are an exact tag, latest, a caret/tilde range or space separated comparators
+	fullyQualifyPackageExpression = regexp.MustCompile(`^(?:(?P<scheme>[a-z][a-z0-9+\-]*):(?:(?P<url>//)(?P<authority>(?:[\w\-\.]+@)?[\w\-\.]*(?::\d+)?)/)?)?` +
+		`(?P<path>[\w\-\.]+(?:\/[\w\-\.]+)*)` +
+		`(?::(?P<version>[\w\.]+|[\^~]v?\d+(\.\d+){0,2}|(>=|<=|>|<|=)v?\d+(\.\d+){0,2}( +(>=|<=|>|<|=)v?\d+(\.\d+){0,2})*))?$`)
 )
 
+// FullyQualifyPackage is a package spec such as org/name:v1.0, github:ghe.corp.example/org/name:^1.2,
+// gitlab:group/sub/name:v1, gitlab://gitlab.corp.example/group/name:v1 or git+ssh://git@host/team/name.git:v1
 type FullyQualifyPackage struct {
+	scheme       string
+	url          string
+	hostname     string
 	organization string
 	name         string
 	version      string
 }
 
 func NewFullyQualifyPackage(fqp string) (FullyQualifyPackage, error) {
-	expression := regexp.MustCompile(`^[\w\-\.]+\/([\w\-\.]+)(\:{1}[\w\.]+)?$`)
-	if false == expression.MatchString(fqp) {
+	match := fullyQualifyPackageExpression.FindStringSubmatch(fqp)
+	if nil == match {
+		return FullyQualifyPackage{}, ErrFullyQualifyPackageInvalidFormat
+	}
+
+	groups := make(map[string]string)
+	for i, group := range fullyQualifyPackageExpression.SubexpNames() {
+		if "" != group {
+			groups[group] = match[i]
+		}
+	}
+
+	fqpVO := FullyQualifyPackage{scheme: groups["scheme"], version: groups["version"]}
+	components := strings.Split(groups["path"], "/")
+
+	switch {
+	case "" != groups["url"] && isGitURLScheme(fqpVO.scheme):
+		fqpVO.url = fmt.Sprintf("%s://%s/%s", groups["scheme"], groups["authority"], groups["path"])
+		fqpVO.hostname = urlHostname(groups["authority"])
+
+		// a url with a single path component has no organization, the host stands for it
+		if 1 == len(components) {
+			components = append([]string{fqpVO.hostname}, components...)
+		}
+
+		components[len(components)-1] = strings.TrimSuffix(components[len(components)-1], ".git")
+	case "" != groups["url"]:
+		fqpVO.hostname = urlHostname(groups["authority"])
+	case ("" == fqpVO.scheme || GithubProviderName == fqpVO.scheme) && len(components) > 2 && strings.Contains(components[0], "."):
+		// only github specs may start with a bare hostname, the first group of other providers may have dots
+		fqpVO.hostname, components = components[0], components[1:]
+	}
+
+	if 2 > len(components) {
 		return FullyQualifyPackage{}, ErrFullyQualifyPackageInvalidFormat
 	}
 
-	versionSeparatorIndex := strings.Index(fqp, ":")
+	fqpVO.organization, fqpVO.name = strings.Join(components[:len(components)-1], "/"), components[len(components)-1]
+
+	if "" == fqpVO.organization || "" == fqpVO.name {
+		return FullyQualifyPackage{}, ErrFullyQualifyPackageInvalidFormat
+	}
+
+	return fqpVO, nil
+}
+
+// isGitURLScheme reports whether scheme://host/path specs of scheme are git remotes, other schemes only name the
+// host of their provider that way
+func isGitURLScheme(scheme string) bool {
+	if GitProviderName == scheme {
+		return true
+	}
+
+	for _, gitScheme := range GitProviderSchemes {
+		if gitScheme == scheme {
+			return true
+		}
+	}
+
+	return false
+}
 
-	components := strings.Split(fqp, "/")
-	version := ""
-	if versionSeparatorIndex > -1 {
-		components = strings.Split(fqp[0:versionSeparatorIndex], "/")
-		version = fqp[versionSeparatorIndex+1:]
+// urlHostname drops the user and the port of an url authority
+func urlHostname(authority string) string {
+	if at := strings.LastIndex(authority, "@"); at >= 0 {
+		authority = authority[at+1:]
 	}
 
-	organization, name := components[0], components[1]
+	if colon := strings.Index(authority, ":"); colon >= 0 {
+		authority = authority[:colon]
+	}
 
-	return FullyQualifyPackage{
-		organization: organization,
-		name:         name,
-		version:      version,
-	}, nil
+	return authority
 }
 
 func (fqp FullyQualifyPackage) String() string {
+	name := fqp.url
+	if "" == name {
+		name = fmt.Sprintf("%s/%s", fqp.organization, fqp.name)
+
+		if "" != fqp.hostname {
+			name = fmt.Sprintf("%s/%s", fqp.hostname, name)
+		}
+
+		if "" != fqp.hostname && "" != fqp.scheme && GithubProviderName != fqp.scheme {
+			name = fmt.Sprintf("%s://%s", fqp.scheme, name)
+		} else if "" != fqp.scheme {
+			name = fmt.Sprintf("%s:%s", fqp.scheme, name)
+		}
+	}
+
 	if "" != fqp.version {
-		return fmt.Sprintf("%s/%s:%s", fqp.organization, fqp.name, fqp.version)
+		return fmt.Sprintf("%s:%s", name, fqp.version)
 	}
 
-	return fmt.Sprintf("%s/%s", fqp.organization, fqp.name)
+	return name
+}
+
+// Scheme is the provider a spec such as gitlab:group/name:v1.0 starts with, empty when the spec doesn't choose one
+func (fqp FullyQualifyPackage) Scheme() string {
+	return fqp.scheme
+}
+
+// URL is the location of url specs such as git+ssh://host/team/name.git:v1 without the version, empty otherwise
+func (fqp FullyQualifyPackage) URL() string {
+	return fqp.url
+}
+
+// Hostname is the host of a spec such as gitlab://gitlab.corp.example/group/name:v1.0. Github specs may also start
+// with it, as in ghe.corp.example/org/name:v1.0, a first component with a dot followed by at least two more is a hostname
+func (fqp FullyQualifyPackage) Hostname() string {
+	return fqp.hostname
 }
 
 func (fqp FullyQualifyPackage) Organization() string {
@@ -61,8 +155,15 @@ func (fqp FullyQualifyPackage) Version() string {
 	return fqp.version
 }
 
+func (fqp FullyQualifyPackage) IsVersionConstraint() bool {
+	return IsVersionConstraint(fqp.version)
+}
+
 func (fqp *FullyQualifyPackage) Equals(other FullyQualifyPackage) bool {
 	return fqp.name == other.name &&
+		fqp.scheme == other.scheme &&
+		fqp.url == other.url &&
+		fqp.hostname == other.hostname &&
 		fqp.organization == other.organization &&
 		fqp.version == other.version
 }
@@ -70,6 +171,9 @@ func (fqp *FullyQualifyPackage) Equals(other FullyQualifyPackage) bool {
 func (fqp FullyQualifyPackage) clone() FullyQualifyPackage {
 	var clone = new(FullyQualifyPackage)
 
+	clone.scheme = fqp.scheme
+	clone.url = fqp.url
+	clone.hostname = fqp.hostname
 	clone.organization = fqp.organization
 	clone.name = fqp.name
 	clone.version = fqp.version
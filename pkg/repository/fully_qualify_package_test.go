@@ -9,10 +9,30 @@ import (
 func TestNewFullyQualifyPackage(t *testing.T) {
 	t.Run("valid FQP Format", func(t *testing.T) {
 		valid := map[string]FullyQualifyPackage{
-			"organization/name":        {"organization", "name", ""},
-			"organization/name:v1.0":   {"organization", "name", "v1.0"},
-			"organization/name:latest": {"organization", "name", "latest"},
-			"name/organization:2.0":    {"name", "organization", "2.0"},
+			"organization/name":                              {organization: "organization", name: "name"},
+			"organization/name:v1.0":                         {organization: "organization", name: "name", version: "v1.0"},
+			"organization/name:latest":                       {organization: "organization", name: "name", version: "latest"},
+			"name/organization:2.0":                          {organization: "name", name: "organization", version: "2.0"},
+			"organization/name:^1.2":                         {organization: "organization", name: "name", version: "^1.2"},
+			"organization/name:~1.4.0":                       {organization: "organization", name: "name", version: "~1.4.0"},
+			"organization/name:>=1.0 <2.0":                   {organization: "organization", name: "name", version: ">=1.0 <2.0"},
+			"group/subgroup/name:v1.0":                       {organization: "group/subgroup", name: "name", version: "v1.0"},
+			"group/sub/team/name":                            {organization: "group/sub/team", name: "name"},
+			"ghe.corp.example/org/name:v1.0":                 {hostname: "ghe.corp.example", organization: "org", name: "name", version: "v1.0"},
+			"gitlab.corp/group/sub/name":                     {hostname: "gitlab.corp", organization: "group/sub", name: "name"},
+			"my.org/name:v1.0":                               {organization: "my.org", name: "name", version: "v1.0"},
+			"github:org/name:v1":                             {scheme: "github", organization: "org", name: "name", version: "v1"},
+			"gitlab:group/sub/name:^1.2":                     {scheme: "gitlab", organization: "group/sub", name: "name", version: "^1.2"},
+			"gitea://git.corp.example/org/name":              {scheme: "gitea", hostname: "git.corp.example", organization: "org", name: "name"},
+			"gitlab://gitlab.corp/group/sub/name:v1":         {scheme: "gitlab", hostname: "gitlab.corp", organization: "group/sub", name: "name", version: "v1"},
+			"gitlab:my.team/sub/name":                        {scheme: "gitlab", organization: "my.team/sub", name: "name"},
+			"github:ghe.corp.example/org/name":               {scheme: "github", hostname: "ghe.corp.example", organization: "org", name: "name"},
+			"registry://registry.corp.example/org/name:v1":   {scheme: "registry", hostname: "registry.corp.example", organization: "org", name: "name", version: "v1"},
+			"registry:registry.corp.example/org/name:v1":     {scheme: "registry", organization: "registry.corp.example/org", name: "name", version: "v1"},
+			"http:internal/name:v1":                          {scheme: "http", organization: "internal", name: "name", version: "v1"},
+			"git+ssh://git@host.example:22/team/tool.git:v1": {scheme: "git+ssh", url: "git+ssh://git@host.example:22/team/tool.git", hostname: "host.example", organization: "team", name: "tool", version: "v1"},
+			"git+ssh://host/repo.git:v1":                     {scheme: "git+ssh", url: "git+ssh://host/repo.git", hostname: "host", organization: "host", name: "repo", version: "v1"},
+			"git+file:///srv/git/name.git":                   {scheme: "git+file", url: "git+file:///srv/git/name.git", organization: "srv/git", name: "name"},
 		}
 
 		for fqp, expected := range valid {
@@ -29,12 +49,21 @@ func TestNewFullyQualifyPackage(t *testing.T) {
 			"organization//name",
 			"/organization/name",
 			"organization/name::",
-			"organization/name:^1.0",
-			"organization/name:~1.0",
+			"organization/name:^",
+			"organization/name:~1.0.0.0",
+			"organization/name:>=1.0<2.0",
+			"organization/name:^1.0 <2.0",
 			"organization/name:1-0",
 			"organization /name:1.0",
 			"organization/name :1.0",
 			"organization\\/name:1.0",
+			"group/subgroup/:v1.0",
+			"name:v1.0",
+			"github:name:v1.0",
+			"git+file:///name.git:v1",
+			"GitHub:org/name",
+			"github://org:v1",
+			"gitlab://gitlab.corp/name:v1",
 		}
 
 		for _, fqp := range invalid {
@@ -64,3 +93,28 @@ func TestNewFullyQualifyPackage(t *testing.T) {
 		}
 	})
 }
+
+func TestFullyQualifyPackageVersionConstraint(t *testing.T) {
+	constraints := map[string]bool{
+		"organization/name:^1.2":       true,
+		"organization/name:~1.4.0":     true,
+		"organization/name:>=1.0 <2.0": true,
+		"organization/name:v1.0":       false,
+		"organization/name:latest":     false,
+		"organization/name":            false,
+	}
+
+	for fqp, expected := range constraints {
+		fqpVO, err := NewFullyQualifyPackage(fqp)
+		require.Nil(t, err)
+
+		assert.Equal(t, expected, fqpVO.IsVersionConstraint(), fqp)
+	}
+}
+
+func TestPackageInstallName(t *testing.T) {
+	assert.Equal(t, "organization-name", PackageInstallName("organization", "name"))
+	assert.Equal(t, "group%2Fsub-name", PackageInstallName("group/sub", "name"))
+	assert.Equal(t, "my-org-name", PackageInstallName("my-org", "name"))
+	assert.Equal(t, "ghe.corp.example%2Fmy-org-name", PackageInstallName("ghe.corp.example/my-org", "name"))
+}
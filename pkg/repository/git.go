@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/rafaelcalleja/go-bpkg/pkg/run"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	GitProviderName         = "git"
+	DefaultGitBinary        = "git"
+	gitTagsRefPrefix        = "refs/tags/"
+	gitDefaultArchiveFormat = ArchiveFormatTarGz
+)
+
+// GitProviderSchemes are the url spec schemes installed through git, e.g. git+ssh://git@host/team/name.git:v1
+var GitProviderSchemes = []string{"git+ssh", "git+https", "git+http", "git+file"}
+
+func init() {
+	RegisterProvider(GitProviderName, newGitProviders)
+
+	for _, scheme := range GitProviderSchemes {
+		RegisterProvider(scheme, newGitProviders)
+	}
+}
+
+var ErrGitRemoteNotSet = errors.New("git provider remote can't be empty")
+
+// GitProvider installs tags of any git remote, versions come from git ls-remote and archives from git archive of a
+// shallow bare clone, the git binary runs through run.PrepareCmd
+type GitProvider struct {
+	remote  string
+	binary  string
+	archive string
+}
+
+func NewGitProviderWith(options ...func(*GitProvider) error) (*GitProvider, error) {
+	var gitProvider = &GitProvider{
+		binary:  DefaultGitBinary,
+		archive: gitDefaultArchiveFormat,
+	}
+
+	for _, option := range options {
+		err := option(gitProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if "" == gitProvider.remote {
+		return nil, ErrGitRemoteNotSet
+	}
+
+	return gitProvider, nil
+}
+
+// GitWithRemote sets the remote template, e.g. git@gitlab.corp:{{.Organization}}/{{.Name}}.git or file:///srv/git/{{.Name}}.git
+func GitWithRemote(remote string) func(*GitProvider) error {
+	return func(g *GitProvider) error {
+		g.remote = remote
+		return nil
+	}
+}
+
+func GitWithBinary(binary string) func(*GitProvider) error {
+	return func(g *GitProvider) error {
+		g.binary = binary
+		return nil
+	}
+}
+
+func GitWithArchiveFormat(archive string) func(*GitProvider) error {
+	return func(g *GitProvider) error {
+		g.archive = archive
+		return nil
+	}
+}
+
+func NewGitProvider(remote string) (*GitProvider, error) {
+	return NewGitProviderWith(GitWithRemote(remote))
+}
+
+// newGitProviders clones the url of url specs, otherwise the remote template or the package at https://<hostname>
+func newGitProviders(options ProviderOptions) (ReleasesProvider, ReleaseVersionFinder, error) {
+	remote := options.Remote
+	if "" != options.URL {
+		remote = strings.TrimPrefix(options.URL, "git+")
+	}
+
+	if "" == remote {
+		hostname := options.Hostname
+		if "" == hostname {
+			hostname = GithubRepository
+		}
+
+		remote = fmt.Sprintf("https://%s/{{.Organization}}/{{.Name}}.git", hostname)
+	}
+
+	provider, err := NewGitProviderWith(
+		GitWithRemote(remote),
+		GitWithArchiveFormat(options.archive()),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return provider, provider, nil
+}
+
+func (g *GitProvider) ProviderName() string {
+	return GitProviderName
+}
+
+// DownloadVariant tells apart remotes sharing a hostname, file:// remotes have none
+func (g *GitProvider) DownloadVariant() string {
+	return fmt.Sprintf("%s %s", g.remote, g.archive)
+}
+
+// Hostname is the host of the remote, empty for local paths and file:// remotes
+func (g *GitProvider) Hostname() string {
+	remote, err := RenderAssetPattern(g.remote, AssetPatternData{})
+	if err != nil {
+		return ""
+	}
+
+	if parsed, err := url.Parse(remote); err == nil && "" != parsed.Scheme {
+		return parsed.Hostname()
+	}
+
+	// scp-like syntax user@host:path
+	if index := strings.Index(remote, ":"); index > 0 && false == strings.Contains(remote[:index], "/") {
+		host := remote[:index]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+
+		return host
+	}
+
+	return ""
+}
+
+func (g *GitProvider) remoteURL(organization string, name string) (string, error) {
+	return RenderAssetPattern(g.remote, AssetPatternData{Organization: organization, Name: name})
+}
+
+func (g *GitProvider) git(args ...string) run.Runnable {
+	return run.PrepareCmd(exec.Command(g.binary, args...))
+}
+
+// Download shallow clones the tag into a temporary bare repository and writes its git archive to downloadDir
+func (g *GitProvider) Download(releaseVersion *ReleaseVersion, downloadDir string) error {
+	remote, err := g.remoteURL(releaseVersion.Organization, releaseVersion.Name)
+	if err != nil {
+		return err
+	}
+
+	cloneDirectory, err := os.MkdirTemp("", "go-bpkg-git-clone")
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(cloneDirectory)
+
+	tag := releaseVersion.Version()
+	err = g.git("clone", "--quiet", "--bare", "--depth", "1", "--branch", tag, "--", remote, cloneDirectory).Run()
+	if err != nil {
+		return fmt.Errorf("Error cloning tag %s of %s: %w", tag, remote, err)
+	}
+
+	folder := fmt.Sprintf("%s-%s", releaseVersion.Name, releaseVersion.VersionWithOutV())
+	archivePath := filepath.Join(downloadDir, fmt.Sprintf("%s.%s", folder, g.archive))
+
+	err = g.git("--git-dir", cloneDirectory, "archive", "--format", g.archive, "--prefix", folder+"/",
+		"--output", archivePath, tag).Run()
+	if err != nil {
+		return fmt.Errorf("Error archiving tag %s of %s: %w", tag, remote, err)
+	}
+
+	return nil
+}
+
+// Latest is the highest stable semver tag, or the highest tag in lexical order when none is semver
+func (g *GitProvider) Latest(organization string, name string) (string, error) {
+	tags, err := g.List(organization, name)
+	if err != nil {
+		return "", err
+	}
+
+	constraint, _ := NewVersionConstraint(latestStableVersion)
+	if latest, err := constraint.Highest(tags); err == nil {
+		return latest, nil
+	}
+
+	if 0 == len(tags) {
+		return "", fmt.Errorf("%w: %s/%s", ErrNoMatchingReleaseVersion, organization, name)
+	}
+
+	return tags[len(tags)-1], nil
+}
+
+// List returns the tags of the remote sorted by name
+func (g *GitProvider) List(organization string, name string) ([]string, error) {
+	remote, err := g.remoteURL(organization, name)
+	if err != nil {
+		return []string{}, err
+	}
+
+	output, err := g.git("ls-remote", "--tags", "--refs", "--", remote).Output()
+	if err != nil {
+		return []string{}, fmt.Errorf("Error listing tags of %s: %w", remote, err)
+	}
+
+	return parseGitTags(string(output)), nil
+}
+
+// parseGitTags reads the "<sha>\trefs/tags/<tag>" lines printed by git ls-remote
+func parseGitTags(output string) []string {
+	tags := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if 2 != len(fields) || false == strings.HasPrefix(fields[1], gitTagsRefPrefix) {
+			continue
+		}
+
+		tag := strings.TrimSuffix(strings.TrimPrefix(fields[1], gitTagsRefPrefix), "^{}")
+		if false == seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	sort.Strings(tags)
+
+	return tags
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/rafaelcalleja/go-bpkg/pkg/run"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+type stubGitCmd struct {
+	args   []string
+	output string
+	err    error
+}
+
+func (s *stubGitCmd) Output() ([]byte, error) {
+	return []byte(s.output), s.err
+}
+
+// Run writes the testdata archive to the --output of git archive
+func (s *stubGitCmd) Run() error {
+	if nil != s.err {
+		return s.err
+	}
+
+	for i, arg := range s.args {
+		if "--output" == arg {
+			return files.CopyFile("testdata/sourceTarFile.tar.gz", s.args[i+1])
+		}
+	}
+
+	return nil
+}
+
+func stubGit(t *testing.T, output string, err error) *[][]string {
+	calls := make([][]string, 0)
+	original := run.PrepareCmd
+
+	run.PrepareCmd = func(cmd *exec.Cmd) run.Runnable {
+		calls = append(calls, cmd.Args)
+
+		return &stubGitCmd{args: cmd.Args, output: output, err: err}
+	}
+
+	t.Cleanup(func() {
+		run.PrepareCmd = original
+	})
+
+	return &calls
+}
+
+func TestGitProviderFinder(t *testing.T) {
+	calls := stubGit(t, "a1\trefs/tags/v1.0\nb2\trefs/tags/v1.1\nc3\trefs/tags/v2.0-rc.1\nd4\trefs/heads/main\n", nil)
+
+	provider, err := NewGitProvider("git@git.corp.example:{{.Organization}}/{{.Name}}.git")
+	require.Nil(t, err)
+
+	assert.Equal(t, GitProviderName, provider.ProviderName())
+	assert.Equal(t, "git.corp.example", provider.Hostname())
+
+	versions, err := provider.List("rafaelcalleja", "assert.sh")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"v1.0", "v1.1", "v2.0-rc.1"}, versions)
+
+	latest, err := provider.Latest("rafaelcalleja", "assert.sh")
+	require.Nil(t, err)
+	assert.Equal(t, "v1.1", latest)
+
+	assert.Equal(t, []string{"git", "ls-remote", "--tags", "--refs", "--", "git@git.corp.example:rafaelcalleja/assert.sh.git"}, (*calls)[0])
+
+	_, err = NewGitProvider("")
+	assert.True(t, errors.Is(err, ErrGitRemoteNotSet))
+
+	hostnames := map[string]string{
+		"https://git.corp.example/{{.Organization}}/{{.Name}}.git":    "git.corp.example",
+		"ssh://git@git.corp.example:2222/{{.Organization}}/{{.Name}}": "git.corp.example",
+		"file:///srv/git/{{.Name}}.git":                               "",
+		"/srv/git/{{.Name}}.git":                                      "",
+	}
+
+	for remote, expected := range hostnames {
+		provider, err := NewGitProvider(remote)
+		require.Nil(t, err)
+		assert.Equal(t, expected, provider.Hostname(), remote)
+	}
+}
+
+func TestGitProviderDownloadAndInstall(t *testing.T) {
+	calls := stubGit(t, "", nil)
+
+	installDir, _ := os.MkdirTemp("", "temp-test-install-folder")
+	defer os.RemoveAll(installDir)
+
+	provider, err := NewGitProvider("https://git.corp.example/{{.Organization}}/{{.Name}}.git")
+	require.Nil(t, err)
+
+	releaseVersion := NewReleaseVersion("rafaelcalleja", "assert.sh", "v1.1")
+	require.Nil(t, releaseVersion.DownloadAndInstallAsset(provider, installDir))
+	assert.True(t, releaseVersion.IsVersionInstalled("v1.1", installDir))
+
+	require.Len(t, *calls, 2)
+	assert.Equal(t, []string{"git", "clone", "--quiet", "--bare", "--depth", "1", "--branch", "v1.1", "--",
+		"https://git.corp.example/rafaelcalleja/assert.sh.git"}, (*calls)[0][:10])
+	assert.Equal(t, []string{"archive", "--format", "tar.gz", "--prefix", "assert.sh-1.1/", "--output"}, (*calls)[1][3:9])
+
+	stubGit(t, "", errors.New("fatal: Remote branch v9.9 not found"))
+
+	missing := NewReleaseVersion("rafaelcalleja", "assert.sh", "v9.9")
+	assert.NotNil(t, missing.DownloadAndInstallAsset(provider, installDir))
+}
+
+func TestGitProviderWithLocalRemote(t *testing.T) {
+	if _, err := exec.LookPath(DefaultGitBinary); err != nil {
+		t.Skip("git binary not found")
+	}
+
+	remoteDir, _ := os.MkdirTemp("", "temp-test-git-remote")
+	defer os.RemoveAll(remoteDir)
+
+	worktree := filepath.Join(remoteDir, "worktree")
+	require.Nil(t, os.MkdirAll(worktree, os.ModePerm))
+	manifest := `{"name": "term", "version": "v0.0.2", "scripts": ["term.sh"]}`
+	require.Nil(t, os.WriteFile(filepath.Join(worktree, "package.json"), []byte(manifest), 0644))
+	require.Nil(t, os.WriteFile(filepath.Join(worktree, "term.sh"), []byte("#!/bin/bash\n"), 0755))
+
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "release"},
+		{"tag", "v0.0.2"},
+		{"clone", "--quiet", "--bare", worktree, filepath.Join(remoteDir, "term.git")},
+	} {
+		cmd := exec.Command(DefaultGitBinary, args...)
+		cmd.Dir = worktree
+		output, err := cmd.CombinedOutput()
+		require.Nil(t, err, string(output))
+	}
+
+	provider, err := NewGitProvider(fmt.Sprintf("file://%s/{{.Name}}.git", filepath.ToSlash(remoteDir)))
+	require.Nil(t, err)
+
+	latest, err := provider.Latest("bpkg", "term")
+	require.Nil(t, err)
+	assert.Equal(t, "v0.0.2", latest)
+
+	installDir, _ := os.MkdirTemp("", "temp-test-install-folder")
+	defer os.RemoveAll(installDir)
+
+	releaseVersion := NewReleaseVersion("bpkg", "term", "v0.0.2")
+	require.Nil(t, releaseVersion.DownloadAndInstallAsset(provider, installDir))
+	assert.True(t, releaseVersion.IsVersionInstalled("v0.0.2", installDir))
+}
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	GiteaProviderName         = "gitea"
+	ForgejoProviderName       = "forgejo"
+	GiteaTokenEnv             = "GITEA_TOKEN"
+	DefaultGiteaListLimit     = 100
+	giteaReleasesPageSize     = 50
+	giteaDefaultArchiveFormat = ArchiveFormatTarGz
+)
+
+var ErrGiteaHostnameNotSet = errors.New("gitea provider requires a hostname or a base url")
+
+// forgejo is a gitea fork serving the same API
+func init() {
+	RegisterProvider(GiteaProviderName, newGiteaProviders)
+	RegisterProvider(ForgejoProviderName, newGiteaProviders)
+}
+
+type giteaRelease struct {
+	TagName    string `json:"tag_name"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	Assets     []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// GiteaProvider downloads releases through the API of a Gitea or Forgejo instance, both serve the same /api/v1
+type GiteaProvider struct {
+	hostname  string
+	baseURL   string
+	token     string
+	archive   string
+	asset     string
+	listLimit int
+	client    *http.Client
+}
+
+func NewGiteaProviderWith(options ...func(*GiteaProvider) error) (*GiteaProvider, error) {
+	var giteaProvider = &GiteaProvider{
+		archive:   giteaDefaultArchiveFormat,
+		listLimit: DefaultGiteaListLimit,
+	}
+
+	for _, option := range options {
+		err := option(giteaProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if "" == giteaProvider.baseURL && "" == giteaProvider.hostname {
+		return nil, ErrGiteaHostnameNotSet
+	}
+
+	if "" == giteaProvider.baseURL {
+		giteaProvider.baseURL = fmt.Sprintf("https://%s/api/v1", giteaProvider.hostname)
+	}
+
+	giteaProvider.baseURL = strings.TrimSuffix(giteaProvider.baseURL, "/")
+
+	if "" == giteaProvider.hostname {
+		if parsed, err := url.Parse(giteaProvider.baseURL); err == nil {
+			giteaProvider.hostname = parsed.Host
+		}
+	}
+
+	if nil == giteaProvider.client {
+		giteaProvider.client = &http.Client{Timeout: DefaultHttpTimeout}
+	}
+
+	return giteaProvider, nil
+}
+
+func GiteaWithHostname(hostname string) func(*GiteaProvider) error {
+	return func(g *GiteaProvider) error {
+		g.hostname = hostname
+		return nil
+	}
+}
+
+// GiteaWithBaseURL overrides the https://<hostname>/api/v1 API url
+func GiteaWithBaseURL(baseURL string) func(*GiteaProvider) error {
+	return func(g *GiteaProvider) error {
+		g.baseURL = baseURL
+		return nil
+	}
+}
+
+func GiteaWithToken(token string) func(*GiteaProvider) error {
+	return func(g *GiteaProvider) error {
+		g.token = token
+		return nil
+	}
+}
+
+func GiteaWithArchiveFormat(archive string) func(*GiteaProvider) error {
+	return func(g *GiteaProvider) error {
+		g.archive = archive
+		return nil
+	}
+}
+
+func GiteaWithAssetPattern(pattern string) func(*GiteaProvider) error {
+	return func(g *GiteaProvider) error {
+		g.asset = pattern
+		return nil
+	}
+}
+
+func GiteaWithListLimit(limit int) func(*GiteaProvider) error {
+	return func(g *GiteaProvider) error {
+		g.listLimit = limit
+		return nil
+	}
+}
+
+func GiteaWithClient(client *http.Client) func(*GiteaProvider) error {
+	return func(g *GiteaProvider) error {
+		g.client = client
+		return nil
+	}
+}
+
+// NewGiteaProvider authenticates with $GITEA_TOKEN when it is set
+func NewGiteaProvider(hostname string, options ...func(*GiteaProvider) error) (*GiteaProvider, error) {
+	return NewGiteaProviderWith(append([]func(*GiteaProvider) error{
+		GiteaWithHostname(hostname),
+		GiteaWithToken(os.Getenv(GiteaTokenEnv)),
+	}, options...)...)
+}
+
+func newGiteaProviders(options ProviderOptions) (ReleasesProvider, ReleaseVersionFinder, error) {
+	provider, err := NewGiteaProvider(
+		options.Hostname,
+		GiteaWithArchiveFormat(options.archive()),
+		GiteaWithAssetPattern(options.Asset),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return provider, provider, nil
+}
+
+func (g *GiteaProvider) ProviderName() string {
+	return GiteaProviderName
+}
+
+func (g *GiteaProvider) Hostname() string {
+	return g.hostname
+}
+
+func (g *GiteaProvider) AssetPattern() string {
+	return g.asset
+}
+
+// DownloadVariant is the asset pattern or the archive format downloaded
+func (g *GiteaProvider) DownloadVariant() string {
+	if "" != g.asset {
+		return g.asset
+	}
+
+	return g.archive
+}
+
+func (g *GiteaProvider) repositoryURL(organization string, name string) string {
+	return fmt.Sprintf("%s/repos/%s/%s", g.baseURL, url.PathEscape(organization), url.PathEscape(name))
+}
+
+func (g *GiteaProvider) forge() forgeClient {
+	return forgeClient{
+		client:      g.client,
+		baseURL:     g.baseURL,
+		token:       g.token,
+		tokenHeader: "Authorization",
+		tokenFormat: "token %s",
+	}
+}
+
+func (g *GiteaProvider) release(releaseVersion *ReleaseVersion) (giteaRelease, error) {
+	var release giteaRelease
+
+	releaseURL := fmt.Sprintf("%s/releases/tags/%s", g.repositoryURL(releaseVersion.Organization, releaseVersion.Name),
+		url.PathEscape(releaseVersion.Version()))
+
+	_, err := g.forge().getJSON(releaseURL, &release)
+
+	return release, err
+}
+
+// releases returns the published releases newest first, drafts are skipped
+func (g *GiteaProvider) releases(organization string, name string, limit int) ([]giteaRelease, error) {
+	published := make([]giteaRelease, 0)
+
+	for page := 1; len(published) < limit; page++ {
+		var releases []giteaRelease
+
+		releasesURL := fmt.Sprintf("%s/releases?page=%d&limit=%d", g.repositoryURL(organization, name), page,
+			giteaReleasesPageSize)
+
+		if _, err := g.forge().getJSON(releasesURL, &releases); err != nil {
+			return nil, err
+		}
+
+		for _, release := range releases {
+			if false == release.Draft && len(published) < limit {
+				published = append(published, release)
+			}
+		}
+
+		if len(releases) < giteaReleasesPageSize {
+			break
+		}
+	}
+
+	return published, nil
+}
+
+// Download fetches the attachment matching the asset pattern or the source archive of the tag
+func (g *GiteaProvider) Download(releaseVersion *ReleaseVersion, downloadDir string) error {
+	if "" != g.asset {
+		return g.forge().downloadAsset(releaseVersion, g.asset, g.releaseFiles, downloadDir)
+	}
+
+	archiveURL := fmt.Sprintf("%s/archive/%s.%s", g.repositoryURL(releaseVersion.Organization, releaseVersion.Name),
+		url.PathEscape(releaseVersion.Version()), g.archive)
+
+	archiveName := fmt.Sprintf("%s-%s.%s", releaseVersion.Name, releaseVersion.VersionWithOutV(), g.archive)
+
+	return g.forge().download(archiveURL, filepath.Join(downloadDir, archiveName))
+}
+
+// DownloadChecksums only applies to attachments, source archives are generated by the forge
+func (g *GiteaProvider) DownloadChecksums(releaseVersion *ReleaseVersion, downloadDir string) error {
+	if "" == g.asset {
+		return ErrChecksumsNotPublished
+	}
+
+	return g.forge().downloadChecksums(releaseVersion, g.releaseFiles, downloadDir)
+}
+
+func (g *GiteaProvider) DownloadSignature(releaseVersion *ReleaseVersion, assetName string, downloadDir string) error {
+	return g.forge().downloadSignature(releaseVersion, assetName, g.releaseFiles, downloadDir)
+}
+
+func (g *GiteaProvider) releaseFiles(releaseVersion *ReleaseVersion) ([]forgeReleaseFile, error) {
+	release, err := g.release(releaseVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]forgeReleaseFile, 0, len(release.Assets))
+	for _, attachment := range release.Assets {
+		files = append(files, forgeReleaseFile{Name: attachment.Name, URL: attachment.BrowserDownloadURL})
+	}
+
+	return files, nil
+}
+
+// Latest is the newest release that is neither a draft nor a pre-release
+func (g *GiteaProvider) Latest(organization string, name string) (string, error) {
+	releases, err := g.releases(organization, name, g.listLimit)
+	if err != nil {
+		return "", err
+	}
+
+	for _, release := range releases {
+		if false == release.Prerelease {
+			return release.TagName, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s/%s", ErrNoMatchingReleaseVersion, organization, name)
+}
+
+func (g *GiteaProvider) List(organization string, name string) ([]string, error) {
+	releases, err := g.releases(organization, name, g.listLimit)
+	if err != nil {
+		return []string{}, err
+	}
+
+	tags := make([]string, 0, len(releases))
+	for _, release := range releases {
+		tags = append(tags, release.TagName)
+	}
+
+	return tags, nil
+}
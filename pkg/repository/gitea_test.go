@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestGiteaServer(t *testing.T, token string) *httptest.Server {
+	firstPage := []map[string]interface{}{
+		{"tag_name": "v3.0", "draft": true},
+		{"tag_name": "v2.0-rc.1", "prerelease": true},
+	}
+	for minor := giteaReleasesPageSize - 3; minor >= 0; minor-- {
+		firstPage = append(firstPage, map[string]interface{}{"tag_name": fmt.Sprintf("v1.%d", minor)})
+	}
+
+	releases, err := json.Marshal(firstPage)
+	require.Nil(t, err)
+
+	return newTestForgeServer(t, "Authorization", "token %s", token, map[string]testForgeRoute{
+		"/api/v1/repos/rafaelcalleja/assert.sh/releases?1": {body: string(releases)},
+		"/api/v1/repos/rafaelcalleja/assert.sh/releases?2": {body: `[{"tag_name": "v0.1"}]`},
+		"/api/v1/repos/rafaelcalleja/assert.sh/releases/tags/v1.1?": {body: `{"tag_name": "v1.1", "assets": [
+			{"name": "assert.tar.gz", "browser_download_url": "{{.URL}}/attachments/1"},
+			{"name": "assert.tar.gz.sig", "browser_download_url": "{{.URL}}/attachments/2"}
+		]}`},
+		"/api/v1/repos/rafaelcalleja/assert.sh/archive/v1.1.tar.gz?": {body: testForgeArchive},
+		"/attachments/1?": {body: testForgeArchive},
+		"/attachments/2?": {body: "signature"},
+	})
+}
+
+func TestGiteaProviderHostname(t *testing.T) {
+	_, err := NewGiteaProviderWith()
+	assert.True(t, errors.Is(err, ErrGiteaHostnameNotSet))
+
+	byHostname, err := NewGiteaProvider("gitea.corp.example")
+	require.Nil(t, err)
+	assert.Equal(t, "gitea.corp.example", byHostname.Hostname())
+}
+
+func TestGiteaProviderAssets(t *testing.T) {
+	server := newTestGiteaServer(t, "secret")
+	defer server.Close()
+
+	releaseVersion := NewReleaseVersion("rafaelcalleja", "assert.sh", "v1.1")
+
+	withAsset, err := NewGiteaProviderWith(
+		GiteaWithBaseURL(server.URL+"/api/v1"),
+		GiteaWithToken("secret"),
+		GiteaWithAssetPattern("assert*"),
+	)
+	require.Nil(t, err)
+
+	downloadDir, _ := os.MkdirTemp("", "temp-test-download-folder")
+	defer os.RemoveAll(downloadDir)
+
+	err = withAsset.Download(&releaseVersion, downloadDir)
+	assert.True(t, errors.Is(err, ErrAmbiguousReleaseAsset))
+
+	attachment, err := NewGiteaProviderWith(
+		GiteaWithBaseURL(server.URL+"/api/v1"),
+		GiteaWithToken("secret"),
+		GiteaWithAssetPattern("assert.tar.gz"),
+	)
+	require.Nil(t, err)
+
+	require.Nil(t, attachment.Download(&releaseVersion, downloadDir))
+	assert.FileExists(t, filepath.Join(downloadDir, "assert.tar.gz"))
+
+	require.Nil(t, attachment.DownloadSignature(&releaseVersion, "assert.tar.gz", downloadDir))
+	assert.FileExists(t, filepath.Join(downloadDir, "assert.tar.gz.sig"))
+
+	err = attachment.DownloadChecksums(&releaseVersion, downloadDir)
+	assert.True(t, errors.Is(err, ErrChecksumsNotPublished))
+}
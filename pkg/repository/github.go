@@ -1,19 +1,30 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
-	"github.com/cli/cli/v2/pkg/cmd/release/download"
 	"github.com/cli/cli/v2/pkg/cmdutil"
-	"github.com/spf13/cobra"
+	"path"
+	"strings"
 )
 
-const GithubRepository = "github.com"
+const (
+	GithubRepository   = "github.com"
+	GithubProviderName = "github"
+)
+
+func init() {
+	RegisterProvider(GithubProviderName, newGithubProviders)
+}
+
+var ErrGithubFactoryNotSet = errors.New("github provider requires a gh factory")
 
 type GithubProvider struct {
-	*CommandProvider
+	client   *GithubClient
 	factory  *cmdutil.Factory
 	hostname string
 	archive  string
+	asset    string
 }
 
 func NewGithubProviderWith(options ...func(*GithubProvider) error) (*GithubProvider, error) {
@@ -26,26 +37,34 @@ func NewGithubProviderWith(options ...func(*GithubProvider) error) (*GithubProvi
 		}
 	}
 
-	if nil == githubProvider.CommandProvider {
-		cmdDownload := download.NewCmdDownload(githubProvider.factory, nil)
-		cmdDownload.PersistentFlags().StringP("repo", "R", "", "")
+	if "" == githubProvider.hostname {
+		githubProvider.hostname = GithubRepository
+	}
 
-		githubProvider.CommandProvider = NewCommandProvider(
-			cmdDownload,
-			createGithubProviderPreExecution(githubProvider),
-		)
+	if "" == githubProvider.archive {
+		githubProvider.archive = ArchiveFormatTarGz
+	}
+
+	if nil == githubProvider.client {
+		client, err := newGithubFactoryClient(githubProvider.factory, githubProvider.hostname)
+		if err != nil {
+			return nil, err
+		}
+
+		githubProvider.client = client
 	}
 
 	return githubProvider, nil
 }
 
-func WithCommandProvider(provider *CommandProvider) func(*GithubProvider) error {
+func WithGithubClient(client *GithubClient) func(*GithubProvider) error {
 	return func(g *GithubProvider) error {
-		g.CommandProvider = provider
+		g.client = client
 		return nil
 	}
 }
 
+// WithFactory reads the token of the host from the gh config, $GH_TOKEN and $GITHUB_TOKEN take precedence
 func WithFactory(factory *cmdutil.Factory) func(*GithubProvider) error {
 	return func(g *GithubProvider) error {
 		g.factory = factory
@@ -67,31 +86,186 @@ func WithArchiveFormat(archive string) func(*GithubProvider) error {
 	}
 }
 
+// WithAssetPattern downloads the uploaded release asset matching pattern instead of the source archive
+func WithAssetPattern(pattern string) func(*GithubProvider) error {
+	return func(g *GithubProvider) error {
+		g.asset = pattern
+		return nil
+	}
+}
+
+func (g *GithubProvider) AssetPattern() string {
+	return g.asset
+}
+
+func (g *GithubProvider) ProviderName() string {
+	return GithubProviderName
+}
+
+func (g *GithubProvider) Hostname() string {
+	return g.hostname
+}
+
+// DownloadVariant is the asset pattern or the archive format downloaded
+func (g *GithubProvider) DownloadVariant() string {
+	if "" != g.asset {
+		return g.asset
+	}
+
+	return g.archive
+}
+
 func NewGithubProvider(factory *cmdutil.Factory) *GithubProvider {
 	provider, _ := NewGithubProviderWith(
 		WithHostname(GithubRepository),
 		WithFactory(factory),
-		WithArchiveFormat("tar.gz"),
+		WithArchiveFormat(ArchiveFormatTarGz),
 	)
 
 	return provider
 }
 
-func createGithubProviderPreExecution(provider *GithubProvider) preExecute {
-	return func(command *cobra.Command, releaseVersion *ReleaseVersion, tempDirectory string) {
-		command.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-			provider.factory.BaseRepo = cmdutil.OverrideBaseRepoFunc(provider.factory, fmt.Sprintf("%s/%s/%s", provider.hostname, releaseVersion.Organization, releaseVersion.Name))
-			return nil
+func newGithubProviders(options ProviderOptions) (ReleasesProvider, ReleaseVersionFinder, error) {
+	if nil == options.Factory {
+		return nil, nil, ErrGithubFactoryNotSet
+	}
+
+	hostname := options.Hostname
+	if "" == hostname {
+		hostname = GithubRepository
+	}
+
+	client, err := newGithubFactoryClient(options.Factory, hostname)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider, err := NewGithubProviderWith(
+		WithGithubClient(client),
+		WithHostname(hostname),
+		WithFactory(options.Factory),
+		WithArchiveFormat(options.archive()),
+		WithAssetPattern(options.Asset),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	finder, err := NewGithubVersionFinderWith(
+		FinderWithClient(client),
+		FinderWithHostname(hostname),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return provider, finder, nil
+}
+
+// newGithubFactoryClient authenticates against the API of hostname with the token gh resolves for it, if any
+func newGithubFactoryClient(factory *cmdutil.Factory, hostname string) (*GithubClient, error) {
+	options := []func(*GithubClient) error{GithubClientWithHostname(hostname)}
+
+	if nil != factory {
+		if cfg, err := factory.Config(); err == nil {
+			if token, err := cfg.Get(hostname, "oauth_token"); err == nil {
+				options = append(options, GithubClientWithToken(token))
+			}
 		}
+	}
+
+	return NewGithubClientWith(options...)
+}
+
+// Download fetches the release asset matching the asset pattern or the source archive of the tag
+func (g *GithubProvider) Download(releaseVersion *ReleaseVersion, downloadDir string) error {
+	if "" == g.asset {
+		_, err := g.client.DownloadArchive(releaseVersion.Organization, releaseVersion.Name, releaseVersion.Version(),
+			g.archive, downloadDir)
+
+		return err
+	}
+
+	pattern, err := RenderAssetPattern(g.asset, NewAssetPatternData(releaseVersion))
+	if err != nil {
+		return err
+	}
+
+	assets, err := g.releaseAssets(releaseVersion, []string{pattern})
+	if err != nil {
+		return err
+	}
+
+	switch len(assets) {
+	case 0:
+		return fmt.Errorf("%w: %s", ErrReleaseAssetNotFound, pattern)
+	case 1:
+		return g.client.DownloadAsset(assets[0], downloadDir)
+	default:
+		names := make([]string, 0, len(assets))
+		for _, asset := range assets {
+			names = append(names, asset.Name)
+		}
+
+		return fmt.Errorf("%w: %s", ErrAmbiguousReleaseAsset, strings.Join(names, ", "))
+	}
+}
+
+// DownloadChecksums downloads the release assets matching DefaultChecksumFiles, source archives are generated by
+// github so publisher checksums only cover uploaded assets
+func (g *GithubProvider) DownloadChecksums(releaseVersion *ReleaseVersion, downloadDir string) error {
+	if "" == g.asset {
+		return ErrChecksumsNotPublished
+	}
+
+	assets, err := g.releaseAssets(releaseVersion, DefaultChecksumFiles)
+	if err != nil {
+		return err
+	}
+
+	if 0 == len(assets) {
+		return ErrChecksumsNotPublished
+	}
 
-		args := []string{
-			releaseVersion.Version(),
-			"--dir",
-			tempDirectory,
-			"--archive",
-			provider.archive,
+	for _, asset := range assets {
+		if err = g.client.DownloadAsset(asset, downloadDir); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+func (g *GithubProvider) DownloadSignature(releaseVersion *ReleaseVersion, assetName string, downloadDir string) error {
+	assets, err := g.releaseAssets(releaseVersion, []string{assetName + SignatureExtension})
+	if err != nil {
+		return err
+	}
 
-		command.SetArgs(args)
+	if 0 == len(assets) {
+		return fmt.Errorf("%w: %s%s", ErrSignatureNotFound, assetName, SignatureExtension)
 	}
+
+	return g.client.DownloadAsset(assets[0], downloadDir)
+}
+
+// releaseAssets returns the uploaded assets of the release matching any of patterns
+func (g *GithubProvider) releaseAssets(releaseVersion *ReleaseVersion, patterns []string) ([]GithubReleaseAsset, error) {
+	release, err := g.client.ReleaseByTag(releaseVersion.Organization, releaseVersion.Name, releaseVersion.Version())
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make([]GithubReleaseAsset, 0)
+	for _, asset := range release.Assets {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, asset.Name); matched {
+				assets = append(assets, asset)
+
+				break
+			}
+		}
+	}
+
+	return assets, nil
 }
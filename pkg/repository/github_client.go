@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	GithubAPIURL           = "https://api.github.com"
+	githubReleasesPageSize = 100
+	githubJSONMediaType    = "application/vnd.github+json"
+	githubBinaryMediaType  = "application/octet-stream"
+)
+
+type GithubRelease struct {
+	TagName     string               `json:"tag_name"`
+	Name        string               `json:"name"`
+	Draft       bool                 `json:"draft"`
+	Prerelease  bool                 `json:"prerelease"`
+	PublishedAt time.Time            `json:"published_at"`
+	Assets      []GithubReleaseAsset `json:"assets"`
+}
+
+// GithubReleaseAsset URL is the API url, it serves the binary to authenticated requests of private repositories too
+type GithubReleaseAsset struct {
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	URL                string `json:"url"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// GithubAPIError is an http error answered by the GitHub API with the message of its body
+type GithubAPIError struct {
+	*HttpStatusError
+	Message string
+}
+
+func (e *GithubAPIError) Error() string {
+	if "" == e.Message {
+		return e.HttpStatusError.Error()
+	}
+
+	return fmt.Sprintf("%s: %s", e.HttpStatusError.Error(), e.Message)
+}
+
+func (e *GithubAPIError) Unwrap() error {
+	return e.HttpStatusError
+}
+
+// GithubClient talks to the releases REST API of github.com or a GitHub Enterprise host
+type GithubClient struct {
+	baseURL  string
+	token    string
+	pageSize int
+	client   *http.Client
+}
+
+func NewGithubClientWith(options ...func(*GithubClient) error) (*GithubClient, error) {
+	var githubClient = &GithubClient{
+		baseURL:  GithubAPIURL,
+		pageSize: githubReleasesPageSize,
+	}
+
+	for _, option := range options {
+		err := option(githubClient)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	githubClient.baseURL = strings.TrimSuffix(githubClient.baseURL, "/")
+
+	if nil == githubClient.client {
+		githubClient.client = &http.Client{Timeout: DefaultHttpTimeout}
+	}
+
+	return githubClient, nil
+}
+
+func GithubClientWithBaseURL(baseURL string) func(*GithubClient) error {
+	return func(c *GithubClient) error {
+		c.baseURL = baseURL
+		return nil
+	}
+}
+
+// GithubClientWithHostname uses the API of github.com or the /api/v3 of a GitHub Enterprise host
+func GithubClientWithHostname(hostname string) func(*GithubClient) error {
+	return func(c *GithubClient) error {
+		c.baseURL = GithubAPIURLForHost(hostname)
+		return nil
+	}
+}
+
+func GithubClientWithToken(token string) func(*GithubClient) error {
+	return func(c *GithubClient) error {
+		c.token = token
+		return nil
+	}
+}
+
+func GithubClientWithHTTPClient(client *http.Client) func(*GithubClient) error {
+	return func(c *GithubClient) error {
+		c.client = client
+		return nil
+	}
+}
+
+func GithubAPIURLForHost(hostname string) string {
+	if "" == hostname || GithubRepository == hostname {
+		return GithubAPIURL
+	}
+
+	return fmt.Sprintf("https://%s/api/v3", hostname)
+}
+
+func (c *GithubClient) repositoryURL(organization string, name string) string {
+	return fmt.Sprintf("%s/repos/%s/%s", c.baseURL, url.PathEscape(organization), url.PathEscape(name))
+}
+
+// Releases returns at most limit releases newest first, drafts included
+func (c *GithubClient) Releases(organization string, name string, limit int) ([]GithubRelease, error) {
+	releases := make([]GithubRelease, 0)
+
+	for page := 1; len(releases) < limit; page++ {
+		var pageReleases []GithubRelease
+
+		releasesURL := fmt.Sprintf("%s/releases?per_page=%d&page=%d", c.repositoryURL(organization, name), c.pageSize, page)
+		if err := c.getJSON(releasesURL, &pageReleases); err != nil {
+			return nil, err
+		}
+
+		for _, release := range pageReleases {
+			if len(releases) < limit {
+				releases = append(releases, release)
+			}
+		}
+
+		if len(pageReleases) < c.pageSize {
+			break
+		}
+	}
+
+	return releases, nil
+}
+
+// LatestRelease is the most recent release that is neither a draft nor a pre-release
+func (c *GithubClient) LatestRelease(organization string, name string) (GithubRelease, error) {
+	var release GithubRelease
+
+	return release, c.getJSON(fmt.Sprintf("%s/releases/latest", c.repositoryURL(organization, name)), &release)
+}
+
+func (c *GithubClient) ReleaseByTag(organization string, name string, tag string) (GithubRelease, error) {
+	var release GithubRelease
+
+	releaseURL := fmt.Sprintf("%s/releases/tags/%s", c.repositoryURL(organization, name), url.PathEscape(tag))
+
+	return release, c.getJSON(releaseURL, &release)
+}
+
+// DownloadArchive streams the tar.gz or zip source archive of tag into downloadDir and returns its path, the file is
+// named by the server or <name>-<tag without v>.<format>
+func (c *GithubClient) DownloadArchive(organization string, name string, tag string, format string, downloadDir string) (string, error) {
+	endpoint := "tarball"
+	if ArchiveFormatZip == format {
+		endpoint = "zipball"
+	}
+
+	archiveURL := fmt.Sprintf("%s/%s/%s", c.repositoryURL(organization, name), endpoint, url.PathEscape(tag))
+
+	response, err := c.get(archiveURL, githubBinaryMediaType)
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+
+	fileName := fmt.Sprintf("%s-%s.%s", name, strings.TrimPrefix(tag, "v"), format)
+	if _, params, err := mime.ParseMediaType(response.Header.Get("Content-Disposition")); err == nil {
+		if serverFileName := filepath.Base(params["filename"]); "" != params["filename"] && "." != serverFileName {
+			fileName = serverFileName
+		}
+	}
+
+	filePath := filepath.Join(downloadDir, fileName)
+
+	return filePath, writeResponse(response, archiveURL, filePath)
+}
+
+// DownloadAsset streams asset into downloadDir under its name
+func (c *GithubClient) DownloadAsset(asset GithubReleaseAsset, downloadDir string) error {
+	response, err := c.get(asset.URL, githubBinaryMediaType)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	return writeResponse(response, asset.URL, filepath.Join(downloadDir, filepath.Base(asset.Name)))
+}
+
+func (c *GithubClient) getJSON(apiURL string, data interface{}) error {
+	response, err := c.get(apiURL, githubJSONMediaType)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if err = json.NewDecoder(response.Body).Decode(data); err != nil {
+		return errors.New(fmt.Sprintf("Error unmarsalling %s", apiURL))
+	}
+
+	return nil
+}
+
+// get sends the token to the API host only, redirects to other hosts drop the Authorization header
+func (c *GithubClient) get(apiURL string, accept string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Accept", accept)
+	if "" != c.token {
+		request.Header.Set("Authorization", fmt.Sprintf("token %s", c.token))
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		defer response.Body.Close()
+
+		var body struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(response.Body, 1<<16)).Decode(&body)
+
+		return nil, &GithubAPIError{
+			HttpStatusError: &HttpStatusError{URL: apiURL, StatusCode: response.StatusCode},
+			Message:         body.Message,
+		}
+	}
+
+	return response, nil
+}
+
+func writeResponse(response *http.Response, fileURL string, filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error Creating file %s", filePath))
+	}
+
+	defer file.Close()
+
+	if _, err = io.Copy(file, response.Body); err != nil {
+		return fmt.Errorf("Error Downloading %s: %w", fileURL, err)
+	}
+
+	return nil
+}
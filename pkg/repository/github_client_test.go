@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestGithubServer(t *testing.T, token string) *httptest.Server {
+	archive, err := os.ReadFile("testdata/sourceTarFile.tar.gz")
+	require.Nil(t, err)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if "/codeload/assert.sh-1.1.tar.gz" == r.URL.Path {
+			assert.Empty(t, r.Header.Get("Authorization"))
+			_, _ = w.Write(archive)
+			return
+		}
+
+		if "token "+token != r.Header.Get("Authorization") {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "Bad credentials"}`))
+			return
+		}
+
+		switch r.URL.Path + "?" + r.URL.Query().Get("page") {
+		case "/repos/rafaelcalleja/assert.sh/releases?1":
+			_, _ = w.Write([]byte(`[
+				{"tag_name": "v1.2.0", "published_at": "2022-06-01T00:00:00Z"},
+				{"tag_name": "v2.0.0", "draft": true}
+			]`))
+		case "/repos/rafaelcalleja/assert.sh/releases?2":
+			_, _ = w.Write([]byte(`[{"tag_name": "v1.1.0", "prerelease": true}]`))
+		case "/repos/rafaelcalleja/assert.sh/releases/latest?":
+			_, _ = w.Write([]byte(`{"tag_name": "v1.2.0"}`))
+		case "/repos/rafaelcalleja/assert.sh/releases/tags/v1.1?":
+			_, _ = fmt.Fprintf(w, `{"tag_name": "v1.1", "assets": [
+				{"name": "assert.tar.gz", "size": 9, "url": "%s/repos/rafaelcalleja/assert.sh/releases/assets/1"},
+				{"name": "assert.tar.gz.sig", "url": "%s/repos/rafaelcalleja/assert.sh/releases/assets/2"}
+			]}`, server.URL, server.URL)
+		case "/repos/rafaelcalleja/assert.sh/tarball/v1.1?":
+			http.Redirect(w, r, "http://localhost:"+server.URL[len("http://127.0.0.1:"):]+"/codeload/assert.sh-1.1.tar.gz", http.StatusFound)
+		case "/repos/rafaelcalleja/assert.sh/zipball/v1.1?":
+			w.Header().Set("Content-Disposition", `attachment; filename=rafaelcalleja-assert.sh-abc123.zip`)
+			_, _ = w.Write([]byte("zip"))
+		case "/repos/rafaelcalleja/assert.sh/releases/assets/1?":
+			assert.Equal(t, githubBinaryMediaType, r.Header.Get("Accept"))
+			_, _ = w.Write(archive)
+		case "/repos/rafaelcalleja/assert.sh/releases/assets/2?":
+			_, _ = w.Write([]byte("signature"))
+		case "/repos/rafaelcalleja/broken/releases/latest?":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+
+	return server
+}
+
+func newTestGithubClient(t *testing.T, server *httptest.Server, token string) *GithubClient {
+	client, err := NewGithubClientWith(
+		GithubClientWithBaseURL(server.URL+"/"),
+		GithubClientWithToken(token),
+	)
+	require.Nil(t, err)
+
+	client.pageSize = 2
+
+	return client
+}
+
+func TestGithubClientReleases(t *testing.T) {
+	server := newTestGithubServer(t, "secret")
+	defer server.Close()
+
+	client := newTestGithubClient(t, server, "secret")
+
+	releases, err := client.Releases("rafaelcalleja", "assert.sh", DefaultGithubFinderListLimit)
+	require.Nil(t, err)
+	require.Len(t, releases, 3)
+	assert.Equal(t, "v1.2.0", releases[0].TagName)
+	assert.Equal(t, time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), releases[0].PublishedAt)
+	assert.True(t, releases[1].Draft)
+	assert.True(t, releases[2].Prerelease)
+
+	releases, err = client.Releases("rafaelcalleja", "assert.sh", 1)
+	require.Nil(t, err)
+	assert.Len(t, releases, 1)
+
+	release, err := client.ReleaseByTag("rafaelcalleja", "assert.sh", "v1.1")
+	require.Nil(t, err)
+	require.Len(t, release.Assets, 2)
+	assert.Equal(t, int64(9), release.Assets[0].Size)
+
+	_, err = newTestGithubClient(t, server, "").Releases("rafaelcalleja", "assert.sh", 1)
+
+	var apiError *GithubAPIError
+	require.True(t, errors.As(err, &apiError))
+	assert.Equal(t, http.StatusUnauthorized, apiError.StatusCode)
+	assert.Equal(t, "Bad credentials", apiError.Message)
+	assert.True(t, errors.Is(err, ErrHttpStatus))
+
+	assert.Equal(t, GithubAPIURL, GithubAPIURLForHost(GithubRepository))
+	assert.Equal(t, "https://ghe.corp.example/api/v3", GithubAPIURLForHost("ghe.corp.example"))
+}
+
+func TestGithubClientDownload(t *testing.T) {
+	server := newTestGithubServer(t, "secret")
+	defer server.Close()
+
+	client := newTestGithubClient(t, server, "secret")
+
+	downloadDir, _ := os.MkdirTemp("", "temp-test-download-folder")
+	defer os.RemoveAll(downloadDir)
+
+	archivePath, err := client.DownloadArchive("rafaelcalleja", "assert.sh", "v1.1", ArchiveFormatTarGz, downloadDir)
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(downloadDir, "assert.sh-1.1.tar.gz"), archivePath)
+	assert.FileExists(t, archivePath)
+
+	archivePath, err = client.DownloadArchive("rafaelcalleja", "assert.sh", "v1.1", ArchiveFormatZip, downloadDir)
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(downloadDir, "rafaelcalleja-assert.sh-abc123.zip"), archivePath)
+
+	_, err = client.DownloadArchive("rafaelcalleja", "assert.sh", "v9.9", ArchiveFormatTarGz, downloadDir)
+
+	var statusError *HttpStatusError
+	require.True(t, errors.As(err, &statusError))
+	assert.Equal(t, http.StatusNotFound, statusError.StatusCode)
+}
+
+func TestGithubProviderAssets(t *testing.T) {
+	server := newTestGithubServer(t, "secret")
+	defer server.Close()
+
+	client := newTestGithubClient(t, server, "secret")
+	releaseVersion := NewReleaseVersion("rafaelcalleja", "assert.sh", "v1.1")
+
+	withAsset, err := NewGithubProviderWith(WithGithubClient(client), WithAssetPattern("assert*"))
+	require.Nil(t, err)
+
+	downloadDir, _ := os.MkdirTemp("", "temp-test-download-folder")
+	defer os.RemoveAll(downloadDir)
+
+	err = withAsset.Download(&releaseVersion, downloadDir)
+	assert.True(t, errors.Is(err, ErrAmbiguousReleaseAsset))
+
+	asset, err := NewGithubProviderWith(WithGithubClient(client), WithAssetPattern("assert.tar.gz"))
+	require.Nil(t, err)
+
+	require.Nil(t, asset.Download(&releaseVersion, downloadDir))
+	assert.FileExists(t, filepath.Join(downloadDir, "assert.tar.gz"))
+
+	require.Nil(t, asset.DownloadSignature(&releaseVersion, "assert.tar.gz", downloadDir))
+	assert.FileExists(t, filepath.Join(downloadDir, "assert.tar.gz.sig"))
+
+	err = asset.DownloadChecksums(&releaseVersion, downloadDir)
+	assert.True(t, errors.Is(err, ErrChecksumsNotPublished))
+}
@@ -1,21 +1,21 @@
 package repository
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
-	"github.com/cli/cli/v2/pkg/cmd/release/list"
 	"github.com/cli/cli/v2/pkg/cmdutil"
-	"github.com/spf13/cobra"
-	"strconv"
-	"strings"
+	"net/http"
 )
 
 type GithubVersionFinder struct {
-	command *cobra.Command
-	factory *cmdutil.Factory
-	limit   int
+	client    *GithubClient
+	factory   *cmdutil.Factory
+	hostname  string
+	listLimit int
 }
 
+const DefaultGithubFinderListLimit = 100
+
 func NewGithubVersionFinderWith(options ...func(*GithubVersionFinder) error) (*GithubVersionFinder, error) {
 	var githubVersionFinder = new(GithubVersionFinder)
 
@@ -26,43 +26,66 @@ func NewGithubVersionFinderWith(options ...func(*GithubVersionFinder) error) (*G
 		}
 	}
 
-	if nil == githubVersionFinder.command {
-		cmd := list.NewCmdList(githubVersionFinder.factory, nil)
-		cmd.PersistentFlags().StringP("repo", "R", "", "")
+	if "" == githubVersionFinder.hostname {
+		githubVersionFinder.hostname = GithubRepository
+	}
+
+	if 0 == githubVersionFinder.listLimit {
+		githubVersionFinder.listLimit = DefaultGithubFinderListLimit
+	}
+
+	if nil == githubVersionFinder.client {
+		client, err := newGithubFactoryClient(githubVersionFinder.factory, githubVersionFinder.hostname)
+		if err != nil {
+			return nil, err
+		}
 
-		githubVersionFinder.command = cmd
+		githubVersionFinder.client = client
 	}
 
 	return githubVersionFinder, nil
 }
 
+// Latest is the release github marks as latest, repositories without one have no matching version
 func (g *GithubVersionFinder) Latest(organization string, name string) (string, error) {
-	stdout := g.factory.IOStreams.Out
-	buf := new(bytes.Buffer)
+	release, err := g.client.LatestRelease(organization, name)
 
-	g.command.PersistentPreRunE = func(rootCmd *cobra.Command, args []string) error {
-		g.factory.IOStreams.SetColorEnabled(false)
-		g.factory.BaseRepo = cmdutil.OverrideBaseRepoFunc(g.factory, fmt.Sprintf("%s/%s/%s", "github.com", organization, name))
-		g.factory.IOStreams.Out = buf
+	var statusErr *HttpStatusError
+	if errors.As(err, &statusErr) && http.StatusNotFound == statusErr.StatusCode {
+		return "", fmt.Errorf("%w: %s/%s", ErrNoMatchingReleaseVersion, organization, name)
+	}
 
-		return nil
+	if nil != err {
+		return "", err
 	}
 
-	g.command.SetArgs([]string{"-L", strconv.Itoa(g.limit)})
+	if "" == release.TagName {
+		return "", fmt.Errorf("%w: %s/%s", ErrNoMatchingReleaseVersion, organization, name)
+	}
 
-	err := g.command.Execute()
+	return release.TagName, nil
+}
+
+// List returns every non draft release tag newest first
+func (g *GithubVersionFinder) List(organization string, name string) ([]string, error) {
+	releases, err := g.client.Releases(organization, name, g.listLimit)
 	if nil != err {
-		return "", nil
+		return []string{}, err
 	}
 
-	g.factory.IOStreams.Out = stdout
+	tags := make([]string, 0, len(releases))
+	for _, release := range releases {
+		if false == release.Draft {
+			tags = append(tags, release.TagName)
+		}
+	}
 
-	return strings.Fields(buf.String())[0], nil
+	return tags, nil
 }
 
-func FinderWithCommand(command *cobra.Command) func(*GithubVersionFinder) error {
+func FinderWithClient(client *GithubClient) func(*GithubVersionFinder) error {
 	return func(g *GithubVersionFinder) error {
-		g.command = command
+		g.client = client
 		return nil
 	}
 }
@@ -74,17 +97,29 @@ func FinderWithFactory(factory *cmdutil.Factory) func(*GithubVersionFinder) erro
 	}
 }
 
-func FinderWithLimit(limit int) func(*GithubVersionFinder) error {
+// FinderWithHostname looks up releases on a GitHub Enterprise host, authenticated with its github login --hostname token
+func FinderWithHostname(hostname string) func(*GithubVersionFinder) error {
 	return func(g *GithubVersionFinder) error {
-		g.limit = limit
+		g.hostname = hostname
+		return nil
+	}
+}
+
+func FinderWithListLimit(limit int) func(*GithubVersionFinder) error {
+	return func(g *GithubVersionFinder) error {
+		g.listLimit = limit
 		return nil
 	}
 }
 
 func NewGithubVersionFinder(factory *cmdutil.Factory) *GithubVersionFinder {
+	return NewGithubHostVersionFinder(factory, GithubRepository)
+}
+
+func NewGithubHostVersionFinder(factory *cmdutil.Factory, hostname string) *GithubVersionFinder {
 	finder, _ := NewGithubVersionFinderWith(
 		FinderWithFactory(factory),
-		FinderWithLimit(1),
+		FinderWithHostname(hostname),
 	)
 
 	return finder
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"errors"
+	ghfactory "github.com/cli/cli/v2/pkg/cmd/factory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestGithubVersionFinderLatest(t *testing.T) {
+	server := newTestGithubServer(t, "secret")
+	defer server.Close()
+
+	finder, err := NewGithubVersionFinderWith(FinderWithClient(newTestGithubClient(t, server, "secret")))
+	require.Nil(t, err)
+
+	_, err = finder.Latest("rafaelcalleja", "broken")
+
+	var statusError *HttpStatusError
+	require.True(t, errors.As(err, &statusError))
+	assert.Equal(t, http.StatusInternalServerError, statusError.StatusCode)
+}
+
+func TestGithubVersionFinderHostname(t *testing.T) {
+	factory := ghfactory.New("0.0.0")
+
+	hostnames := map[string]string{"": GithubAPIURL, "ghe.corp.example": "https://ghe.corp.example/api/v3"}
+	for hostname, expected := range hostnames {
+		finder, err := NewGithubVersionFinderWith(
+			FinderWithFactory(factory),
+			FinderWithHostname(hostname),
+		)
+		require.Nil(t, err)
+
+		assert.Equal(t, expected, finder.client.baseURL)
+	}
+
+	provider, err := NewGithubProviderWith(WithFactory(factory), WithHostname("ghe.corp.example"))
+	require.Nil(t, err)
+	assert.Equal(t, "https://ghe.corp.example/api/v3", provider.client.baseURL)
+}
@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	GitlabRepository           = "gitlab.com"
+	GitlabProviderName         = "gitlab"
+	GitlabTokenEnv             = "GITLAB_TOKEN"
+	DefaultGitlabListLimit     = 100
+	gitlabTokenHeader          = "PRIVATE-TOKEN"
+	gitlabReleasesPageSize     = 100
+	gitlabLatestReleasesPage   = 20
+	gitlabNextPageHeader       = "X-Next-Page"
+	gitlabDefaultArchiveFormat = ArchiveFormatTarGz
+)
+
+func init() {
+	RegisterProvider(GitlabProviderName, newGitlabProviders)
+}
+
+type gitlabRelease struct {
+	TagName         string `json:"tag_name"`
+	UpcomingRelease bool   `json:"upcoming_release"`
+	Assets          struct {
+		Links []gitlabReleaseLink `json:"links"`
+	} `json:"assets"`
+}
+
+type gitlabReleaseLink struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+}
+
+func (link gitlabReleaseLink) downloadURL() string {
+	if "" != link.DirectAssetURL {
+		return link.DirectAssetURL
+	}
+
+	return link.URL
+}
+
+// GitlabProvider downloads releases through the GitLab REST API of gitlab.com or a self-managed host, the
+// organization of a package is its group path so nested groups are supported
+type GitlabProvider struct {
+	hostname  string
+	baseURL   string
+	token     string
+	archive   string
+	asset     string
+	listLimit int
+	client    *http.Client
+}
+
+func NewGitlabProviderWith(options ...func(*GitlabProvider) error) (*GitlabProvider, error) {
+	var gitlabProvider = &GitlabProvider{
+		hostname:  GitlabRepository,
+		archive:   gitlabDefaultArchiveFormat,
+		listLimit: DefaultGitlabListLimit,
+	}
+
+	for _, option := range options {
+		err := option(gitlabProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if "" == gitlabProvider.baseURL {
+		gitlabProvider.baseURL = fmt.Sprintf("https://%s/api/v4", gitlabProvider.hostname)
+	}
+
+	gitlabProvider.baseURL = strings.TrimSuffix(gitlabProvider.baseURL, "/")
+
+	if nil == gitlabProvider.client {
+		gitlabProvider.client = &http.Client{Timeout: DefaultHttpTimeout}
+	}
+
+	return gitlabProvider, nil
+}
+
+func GitlabWithHostname(hostname string) func(*GitlabProvider) error {
+	return func(g *GitlabProvider) error {
+		g.hostname = hostname
+		return nil
+	}
+}
+
+// GitlabWithBaseURL overrides the https://<hostname>/api/v4 API url
+func GitlabWithBaseURL(baseURL string) func(*GitlabProvider) error {
+	return func(g *GitlabProvider) error {
+		g.baseURL = baseURL
+		return nil
+	}
+}
+
+func GitlabWithToken(token string) func(*GitlabProvider) error {
+	return func(g *GitlabProvider) error {
+		g.token = token
+		return nil
+	}
+}
+
+func GitlabWithArchiveFormat(archive string) func(*GitlabProvider) error {
+	return func(g *GitlabProvider) error {
+		g.archive = archive
+		return nil
+	}
+}
+
+func GitlabWithAssetPattern(pattern string) func(*GitlabProvider) error {
+	return func(g *GitlabProvider) error {
+		g.asset = pattern
+		return nil
+	}
+}
+
+func GitlabWithListLimit(limit int) func(*GitlabProvider) error {
+	return func(g *GitlabProvider) error {
+		g.listLimit = limit
+		return nil
+	}
+}
+
+func GitlabWithClient(client *http.Client) func(*GitlabProvider) error {
+	return func(g *GitlabProvider) error {
+		g.client = client
+		return nil
+	}
+}
+
+// NewGitlabProvider authenticates with $GITLAB_TOKEN when it is set
+func NewGitlabProvider(hostname string, options ...func(*GitlabProvider) error) (*GitlabProvider, error) {
+	if "" == hostname {
+		hostname = GitlabRepository
+	}
+
+	return NewGitlabProviderWith(append([]func(*GitlabProvider) error{
+		GitlabWithHostname(hostname),
+		GitlabWithToken(os.Getenv(GitlabTokenEnv)),
+	}, options...)...)
+}
+
+func newGitlabProviders(options ProviderOptions) (ReleasesProvider, ReleaseVersionFinder, error) {
+	provider, err := NewGitlabProvider(
+		options.Hostname,
+		GitlabWithArchiveFormat(options.archive()),
+		GitlabWithAssetPattern(options.Asset),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return provider, provider, nil
+}
+
+func (g *GitlabProvider) ProviderName() string {
+	return GitlabProviderName
+}
+
+func (g *GitlabProvider) Hostname() string {
+	return g.hostname
+}
+
+func (g *GitlabProvider) AssetPattern() string {
+	return g.asset
+}
+
+// DownloadVariant is the asset pattern or the archive format downloaded
+func (g *GitlabProvider) DownloadVariant() string {
+	if "" != g.asset {
+		return g.asset
+	}
+
+	return g.archive
+}
+
+// projectURL escapes the full project path so nested groups are a single path segment
+func (g *GitlabProvider) projectURL(organization string, name string) string {
+	project := strings.ReplaceAll(url.PathEscape(fmt.Sprintf("%s/%s", organization, name)), "/", "%2F")
+
+	return fmt.Sprintf("%s/projects/%s", g.baseURL, project)
+}
+
+func (g *GitlabProvider) forge() forgeClient {
+	return forgeClient{
+		client:      g.client,
+		baseURL:     g.baseURL,
+		token:       g.token,
+		tokenHeader: gitlabTokenHeader,
+		tokenFormat: "%s",
+	}
+}
+
+func (g *GitlabProvider) release(releaseVersion *ReleaseVersion) (gitlabRelease, error) {
+	var release gitlabRelease
+
+	releaseURL := fmt.Sprintf("%s/releases/%s", g.projectURL(releaseVersion.Organization, releaseVersion.Name),
+		url.PathEscape(releaseVersion.Version()))
+
+	_, err := g.forge().getJSON(releaseURL, &release)
+
+	return release, err
+}
+
+// Download fetches the release asset matching the asset pattern or the repository archive of the tag
+func (g *GitlabProvider) Download(releaseVersion *ReleaseVersion, downloadDir string) error {
+	if "" != g.asset {
+		return g.forge().downloadAsset(releaseVersion, g.asset, g.releaseFiles, downloadDir)
+	}
+
+	archiveURL := fmt.Sprintf("%s/repository/archive.%s?sha=%s", g.projectURL(releaseVersion.Organization, releaseVersion.Name),
+		g.archive, url.QueryEscape(releaseVersion.Version()))
+
+	archiveName := fmt.Sprintf("%s-%s.%s", releaseVersion.Name, releaseVersion.VersionWithOutV(), g.archive)
+
+	return g.forge().download(archiveURL, filepath.Join(downloadDir, archiveName))
+}
+
+// DownloadChecksums only applies to asset links, repository archives are generated by gitlab
+func (g *GitlabProvider) DownloadChecksums(releaseVersion *ReleaseVersion, downloadDir string) error {
+	if "" == g.asset {
+		return ErrChecksumsNotPublished
+	}
+
+	return g.forge().downloadChecksums(releaseVersion, g.releaseFiles, downloadDir)
+}
+
+func (g *GitlabProvider) DownloadSignature(releaseVersion *ReleaseVersion, assetName string, downloadDir string) error {
+	return g.forge().downloadSignature(releaseVersion, assetName, g.releaseFiles, downloadDir)
+}
+
+func (g *GitlabProvider) releaseFiles(releaseVersion *ReleaseVersion) ([]forgeReleaseFile, error) {
+	release, err := g.release(releaseVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]forgeReleaseFile, 0, len(release.Assets.Links))
+	for _, link := range release.Assets.Links {
+		files = append(files, forgeReleaseFile{Name: link.Name, URL: link.downloadURL()})
+	}
+
+	return files, nil
+}
+
+// Latest is the most recently released tag, upcoming releases are skipped
+func (g *GitlabProvider) Latest(organization string, name string) (string, error) {
+	var releases []gitlabRelease
+
+	releasesURL := fmt.Sprintf("%s/releases?order_by=released_at&sort=desc&per_page=%d", g.projectURL(organization, name),
+		gitlabLatestReleasesPage)
+
+	if _, err := g.forge().getJSON(releasesURL, &releases); err != nil {
+		return "", err
+	}
+
+	for _, release := range releases {
+		if false == release.UpcomingRelease {
+			return release.TagName, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s/%s", ErrNoMatchingReleaseVersion, organization, name)
+}
+
+// List follows the X-Next-Page header until listLimit tags are collected
+func (g *GitlabProvider) List(organization string, name string) ([]string, error) {
+	tags := make([]string, 0)
+
+	for page := "1"; "" != page && len(tags) < g.listLimit; {
+		var releases []gitlabRelease
+
+		releasesURL := fmt.Sprintf("%s/releases?order_by=released_at&sort=desc&per_page=%d&page=%s",
+			g.projectURL(organization, name), gitlabReleasesPageSize, page)
+
+		header, err := g.forge().getJSON(releasesURL, &releases)
+		if err != nil {
+			return []string{}, err
+		}
+
+		for _, release := range releases {
+			if false == release.UpcomingRelease && len(tags) < g.listLimit {
+				tags = append(tags, release.TagName)
+			}
+		}
+
+		page = header.Get(gitlabNextPageHeader)
+		if _, err = strconv.Atoi(page); err != nil {
+			page = ""
+		}
+	}
+
+	return tags, nil
+}
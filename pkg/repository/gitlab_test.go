@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+const testGitlabProject = "/api/v4/projects/group%2Fsub%2Fassert.sh"
+
+func newTestGitlabServer(t *testing.T, token string) *httptest.Server {
+	asset := fmt.Sprintf("assert_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	releases := testForgeRoute{body: `[{"tag_name": "v2.0", "upcoming_release": true}, {"tag_name": "v1.1"}]`, nextPage: "2"}
+
+	return newTestForgeServer(t, gitlabTokenHeader, "%s", token, map[string]testForgeRoute{
+		testGitlabProject + "/releases?":  releases,
+		testGitlabProject + "/releases?1": releases,
+		testGitlabProject + "/releases?2": {body: `[{"tag_name": "v1.0"}]`},
+		testGitlabProject + "/releases/v1.1?": {body: fmt.Sprintf(`{"tag_name": "v1.1", "assets": {"links": [
+			{"name": "%s", "url": "{{.URL}}/uploads/%s"},
+			{"name": "checksums.txt", "direct_asset_url": "{{.URL}}/uploads/checksums.txt"}
+		]}}`, asset, asset)},
+		testGitlabProject + "/repository/archive.tar.gz?": {body: testForgeArchive},
+		"/uploads/" + asset + "?":                         {body: testForgeArchive},
+		"/uploads/checksums.txt?":                         {body: fmt.Sprintf("%s  %s\n", "0000000000000000000000000000000000000000000000000000000000000000", "other.tar.gz")},
+	})
+}
+
+func TestGitlabProviderAssets(t *testing.T) {
+	server := newTestGitlabServer(t, "secret")
+	defer server.Close()
+
+	releaseVersion := NewReleaseVersion("group/sub", "assert.sh", "v1.1")
+
+	withAsset, err := NewGitlabProviderWith(
+		GitlabWithBaseURL(server.URL+"/api/v4"),
+		GitlabWithToken("secret"),
+		GitlabWithAssetPattern("assert_{{.OS}}_{{.Arch}}.tar.gz"),
+	)
+	require.Nil(t, err)
+
+	downloadDir, _ := os.MkdirTemp("", "temp-test-download-folder")
+	defer os.RemoveAll(downloadDir)
+
+	require.Nil(t, withAsset.Download(&releaseVersion, downloadDir))
+	assert.FileExists(t, filepath.Join(downloadDir, fmt.Sprintf("assert_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH)))
+
+	require.Nil(t, withAsset.DownloadChecksums(&releaseVersion, downloadDir))
+	assert.FileExists(t, filepath.Join(downloadDir, "checksums.txt"))
+
+	err = withAsset.DownloadSignature(&releaseVersion, "missing.tar.gz", downloadDir)
+	assert.True(t, errors.Is(err, ErrSignatureNotFound))
+
+	missing, err := NewGitlabProviderWith(
+		GitlabWithBaseURL(server.URL+"/api/v4"),
+		GitlabWithToken("secret"),
+		GitlabWithAssetPattern("missing_*.tar.gz"),
+	)
+	require.Nil(t, err)
+
+	err = missing.Download(&releaseVersion, downloadDir)
+	assert.True(t, errors.Is(err, ErrReleaseAssetNotFound))
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+const (
+	HttpProviderName    = "http"
+	DefaultHttpTimeout  = 5 * time.Minute
+	latestStableVersion = ">=0"
+)
+
+var (
+	ErrHttpStatus            = errors.New("unexpected http status")
+	ErrHttpIndexNotFound     = errors.New("http provider has no index url to list versions")
+	ErrHttpURLTemplateNotSet = errors.New("http provider url template can't be empty")
+)
+
+func init() {
+	RegisterProvider(HttpProviderName, newHttpProviders)
+}
+
+// HttpIndex is the document served at the index url, a plain JSON array of versions is accepted too
+type HttpIndex struct {
+	Versions []string `json:"versions"`
+	Latest   string   `json:"latest,omitempty"`
+}
+
+// HttpProvider downloads archives from a url template such as
+// https://artifacts.corp/{{.Organization}}/{{.Name}}/{{.Version}}.tar.gz and lists versions from a JSON index url
+type HttpProvider struct {
+	urlTemplate string
+	indexURL    string
+	client      *http.Client
+}
+
+func NewHttpProviderWith(options ...func(*HttpProvider) error) (*HttpProvider, error) {
+	var httpProvider = new(HttpProvider)
+
+	for _, option := range options {
+		err := option(httpProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if "" == httpProvider.urlTemplate {
+		return nil, ErrHttpURLTemplateNotSet
+	}
+
+	if nil == httpProvider.client {
+		httpProvider.client = &http.Client{Timeout: DefaultHttpTimeout}
+	}
+
+	return httpProvider, nil
+}
+
+func HttpProviderWithURLTemplate(urlTemplate string) func(*HttpProvider) error {
+	return func(h *HttpProvider) error {
+		h.urlTemplate = urlTemplate
+		return nil
+	}
+}
+
+func HttpProviderWithIndexURL(indexURL string) func(*HttpProvider) error {
+	return func(h *HttpProvider) error {
+		h.indexURL = indexURL
+		return nil
+	}
+}
+
+func HttpProviderWithClient(client *http.Client) func(*HttpProvider) error {
+	return func(h *HttpProvider) error {
+		h.client = client
+		return nil
+	}
+}
+
+func NewHttpProvider(urlTemplate string, indexURL string) (*HttpProvider, error) {
+	return NewHttpProviderWith(
+		HttpProviderWithURLTemplate(urlTemplate),
+		HttpProviderWithIndexURL(indexURL),
+	)
+}
+
+func newHttpProviders(options ProviderOptions) (ReleasesProvider, ReleaseVersionFinder, error) {
+	provider, err := NewHttpProvider(options.Remote, options.IndexURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return provider, provider, nil
+}
+
+func (h *HttpProvider) ProviderName() string {
+	return HttpProviderName
+}
+
+func (h *HttpProvider) Hostname() string {
+	rendered, err := RenderAssetPattern(h.urlTemplate, AssetPatternData{})
+	if err != nil {
+		return ""
+	}
+
+	parsed, err := url.Parse(rendered)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Host
+}
+
+func (h *HttpProvider) DownloadVariant() string {
+	return h.urlTemplate
+}
+
+func (h *HttpProvider) archiveURL(releaseVersion *ReleaseVersion) (string, error) {
+	return RenderAssetPattern(h.urlTemplate, NewAssetPatternData(releaseVersion))
+}
+
+// Download stores the archive under the last path segment of its url
+func (h *HttpProvider) Download(releaseVersion *ReleaseVersion, downloadDir string) error {
+	archiveURL, err := h.archiveURL(releaseVersion)
+	if err != nil {
+		return err
+	}
+
+	name := httpFileName(archiveURL)
+	if "" == name {
+		name = fmt.Sprintf("%s-%s", releaseVersion.Name, releaseVersion.VersionWithOutV())
+	}
+
+	return h.download(archiveURL, filepath.Join(downloadDir, name))
+}
+
+// DownloadSignature fetches the detached signature served next to the archive
+func (h *HttpProvider) DownloadSignature(releaseVersion *ReleaseVersion, assetName string, downloadDir string) error {
+	archiveURL, err := h.archiveURL(releaseVersion)
+	if err != nil {
+		return err
+	}
+
+	err = h.download(archiveURL+SignatureExtension, filepath.Join(downloadDir, assetName+SignatureExtension))
+
+	var statusError *HttpStatusError
+	if errors.As(err, &statusError) && http.StatusNotFound == statusError.StatusCode {
+		return fmt.Errorf("%w: %s%s", ErrSignatureNotFound, archiveURL, SignatureExtension)
+	}
+
+	return err
+}
+
+func (h *HttpProvider) Latest(organization string, name string) (string, error) {
+	index, err := h.index(organization, name)
+	if err != nil {
+		return "", err
+	}
+
+	if "" != index.Latest {
+		return index.Latest, nil
+	}
+
+	constraint, _ := NewVersionConstraint(latestStableVersion)
+	if latest, err := constraint.Highest(index.Versions); err == nil {
+		return latest, nil
+	}
+
+	if 0 == len(index.Versions) {
+		return "", fmt.Errorf("%w: %s/%s", ErrNoMatchingReleaseVersion, organization, name)
+	}
+
+	return index.Versions[len(index.Versions)-1], nil
+}
+
+func (h *HttpProvider) List(organization string, name string) ([]string, error) {
+	index, err := h.index(organization, name)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return index.Versions, nil
+}
+
+func (h *HttpProvider) index(organization string, name string) (HttpIndex, error) {
+	if "" == h.indexURL {
+		return HttpIndex{}, ErrHttpIndexNotFound
+	}
+
+	indexURL, err := RenderAssetPattern(h.indexURL, AssetPatternData{Organization: organization, Name: name})
+	if err != nil {
+		return HttpIndex{}, err
+	}
+
+	response, err := h.get(indexURL)
+	if err != nil {
+		return HttpIndex{}, err
+	}
+
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return HttpIndex{}, err
+	}
+
+	var index HttpIndex
+	if err = json.Unmarshal(body, &index.Versions); err == nil {
+		return index, nil
+	}
+
+	if err = json.Unmarshal(body, &index); err != nil {
+		return HttpIndex{}, errors.New(fmt.Sprintf("Error unmarsalling %s", indexURL))
+	}
+
+	return index, nil
+}
+
+func (h *HttpProvider) download(fileURL string, filePath string) error {
+	return httpDownload(h.client, fileURL, nil, filePath)
+}
+
+func (h *HttpProvider) get(fileURL string) (*http.Response, error) {
+	return httpGet(h.client, fileURL, nil)
+}
+
+// httpDownload streams fileURL into filePath
+func httpDownload(client *http.Client, fileURL string, header http.Header, filePath string) error {
+	response, err := httpGet(client, fileURL, header)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error Creating file %s", filePath))
+	}
+
+	defer file.Close()
+
+	if _, err = io.Copy(file, response.Body); err != nil {
+		return fmt.Errorf("Error Downloading %s: %w", fileURL, err)
+	}
+
+	return nil
+}
+
+// httpGet returns an HttpStatusError for any answer but 200 OK, the caller closes the body
+func httpGet(client *http.Client, fileURL string, header http.Header) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range header {
+		request.Header[key] = values
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if http.StatusOK != response.StatusCode {
+		_ = response.Body.Close()
+
+		return nil, &HttpStatusError{URL: fileURL, StatusCode: response.StatusCode}
+	}
+
+	return response, nil
+}
+
+// HttpStatusError wraps ErrHttpStatus with the url and the status code the server answered
+type HttpStatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HttpStatusError) Error() string {
+	return fmt.Sprintf("%s: %d %s", ErrHttpStatus, e.StatusCode, e.URL)
+}
+
+func (e *HttpStatusError) Unwrap() error {
+	return ErrHttpStatus
+}
+
+func httpFileName(fileURL string) string {
+	parsed, err := url.Parse(fileURL)
+	if err != nil {
+		return ""
+	}
+
+	name := path.Base(parsed.Path)
+	if "." == name || "/" == name {
+		return ""
+	}
+
+	return name
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestHttpServer(t *testing.T) *httptest.Server {
+	archive, err := os.ReadFile("testdata/sourceTarFile.tar.gz")
+	require.Nil(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rafaelcalleja/assert.sh/index.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"versions": ["v1.0", "v1.1", "v2.0-rc.1"]}`))
+	})
+	mux.HandleFunc("/bpkg/term/index.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`["0.0.1", "0.0.2"]`))
+	})
+	mux.HandleFunc("/bpkg/pinned/index.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"versions": ["1.0.0", "2.0.0"], "latest": "1.0.0"}`))
+	})
+	mux.HandleFunc("/rafaelcalleja/assert.sh/1.1.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive)
+	})
+	mux.HandleFunc("/rafaelcalleja/broken/1.1.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestHttpProviderIndex(t *testing.T) {
+	server := newTestHttpServer(t)
+	defer server.Close()
+
+	provider, err := NewHttpProviderWith(
+		HttpProviderWithURLTemplate(server.URL+"/{{.Organization}}/{{.Name}}/{{.Version}}.tar.gz"),
+		HttpProviderWithIndexURL(server.URL+"/{{.Organization}}/{{.Name}}/index.json"),
+		HttpProviderWithClient(server.Client()),
+	)
+	require.Nil(t, err)
+
+	latests := map[string]string{"term": "0.0.2", "pinned": "1.0.0"}
+	for name, expected := range latests {
+		latest, err := provider.Latest("bpkg", name)
+		require.Nil(t, err, name)
+		assert.Equal(t, expected, latest, name)
+	}
+
+	_, err = NewHttpProvider("", "")
+	assert.True(t, errors.Is(err, ErrHttpURLTemplateNotSet))
+
+	withoutIndex, err := NewHttpProvider(server.URL+"/{{.Name}}.tar.gz", "")
+	require.Nil(t, err)
+
+	_, err = withoutIndex.Latest("rafaelcalleja", "assert.sh")
+	assert.True(t, errors.Is(err, ErrHttpIndexNotFound))
+}
+
+func TestHttpProviderDownloadError(t *testing.T) {
+	server := newTestHttpServer(t)
+	defer server.Close()
+
+	installDir, _ := os.MkdirTemp("", "temp-test-install-folder")
+	defer os.RemoveAll(installDir)
+
+	provider, err := NewHttpProviderWith(
+		HttpProviderWithURLTemplate(server.URL+"/{{.Organization}}/{{.Name}}/{{.Version}}.tar.gz"),
+		HttpProviderWithClient(server.Client()),
+	)
+	require.Nil(t, err)
+
+	broken := NewReleaseVersion("rafaelcalleja", "broken", "v1.1")
+	err = broken.DownloadAndInstallAsset(provider, installDir)
+
+	var statusError *HttpStatusError
+	require.True(t, errors.As(err, &statusError))
+	assert.Equal(t, http.StatusInternalServerError, statusError.StatusCode)
+
+	_, err = os.Stat(filepath.Join(installDir, "rafaelcalleja-broken"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestConfigHttpSource(t *testing.T) {
+	config := &Config{HttpSources: map[string]HttpSource{
+		"corp":  {URLTemplate: "https://artifacts.corp/{{.Name}}.tar.gz"},
+		"empty": {IndexURL: "https://artifacts.corp/index.json"},
+	}}
+
+	source, ok := config.HttpSource("corp")
+	assert.True(t, ok)
+	assert.Equal(t, "https://artifacts.corp/{{.Name}}.tar.gz", source.URLTemplate)
+
+	_, ok = config.HttpSource("empty")
+	assert.False(t, ok)
+
+	_, ok = config.HttpSource("github")
+	assert.False(t, ok)
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const LocalProviderName = "local"
+
+var (
+	ErrLocalProviderPathNotFound = errors.New("local package path not found")
+	ErrPackageVersionNotFound    = errors.New("package manifest has no version")
+)
+
+// LocalProvider serves a single package from a tar.gz, a zip or an unpacked directory
+type LocalProvider struct {
+	path string
+}
+
+func NewLocalProviderWith(options ...func(*LocalProvider) error) (*LocalProvider, error) {
+	var localProvider = new(LocalProvider)
+
+	for _, option := range options {
+		err := option(localProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := os.Stat(localProvider.path); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrLocalProviderPathNotFound, localProvider.path)
+	}
+
+	return localProvider, nil
+}
+
+func LocalProviderWithPath(path string) func(*LocalProvider) error {
+	return func(l *LocalProvider) error {
+		l.path = path
+		return nil
+	}
+}
+
+func NewLocalProvider(path string) (*LocalProvider, error) {
+	return NewLocalProviderWith(
+		LocalProviderWithPath(path),
+	)
+}
+
+func (l *LocalProvider) ProviderName() string {
+	return LocalProviderName
+}
+
+func (l *LocalProvider) Hostname() string {
+	return ""
+}
+
+// Uncacheable is true, local packages are read from disk
+func (l *LocalProvider) Uncacheable() bool {
+	return true
+}
+
+func (l *LocalProvider) isDir() bool {
+	info, err := os.Stat(l.path)
+
+	return err == nil && info.IsDir()
+}
+
+// Download copies the archive into downloadDir, a directory is archived first so it goes through the same extraction
+func (l *LocalProvider) Download(releaseVersion *ReleaseVersion, downloadDir string) error {
+	if l.isDir() {
+		path, err := filepath.Abs(l.path)
+		if err != nil {
+			return errors.New(fmt.Sprintf("Error getting absolute path of %s", l.path))
+		}
+
+		return CreateTarGz(path, filepath.Join(downloadDir, fmt.Sprintf("%s.tar.gz", filepath.Base(path))))
+	}
+
+	if err := files.CopyFile(l.path, filepath.Join(downloadDir, filepath.Base(l.path))); err != nil {
+		return errors.New(fmt.Sprintf("Error Coping %s to %s", l.path, downloadDir))
+	}
+
+	return nil
+}
+
+// DownloadSignature copies the signature stored next to the archive, unpacked directories can't be signed
+func (l *LocalProvider) DownloadSignature(releaseVersion *ReleaseVersion, assetName string, downloadDir string) error {
+	signature := l.path + SignatureExtension
+
+	if _, err := os.Stat(signature); l.isDir() || err != nil {
+		return fmt.Errorf("%w: %s", ErrSignatureNotFound, signature)
+	}
+
+	if err := files.CopyFile(signature, filepath.Join(downloadDir, assetName+SignatureExtension)); err != nil {
+		return errors.New(fmt.Sprintf("Error Coping %s to %s", signature, downloadDir))
+	}
+
+	return nil
+}
+
+// Latest is the version declared in the package manifest
+func (l *LocalProvider) Latest(organization string, name string) (string, error) {
+	metadata, err := l.metadata()
+	if err != nil {
+		return "", err
+	}
+
+	if "" == strings.TrimSpace(metadata.Version) {
+		return "", fmt.Errorf("%w: %s", ErrPackageVersionNotFound, l.path)
+	}
+
+	return metadata.Version, nil
+}
+
+func (l *LocalProvider) List(organization string, name string) ([]string, error) {
+	version, err := l.Latest(organization, name)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return []string{version}, nil
+}
+
+func (l *LocalProvider) metadata() (*PackageInstaller, error) {
+	if l.isDir() {
+		return NewPackageInstallerFromFileName(filepath.Join(l.path, DefaultPackageFile))
+	}
+
+	tempDirectory, err := os.MkdirTemp("", "temp-local-package")
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Error Creating temporal dir %s", tempDirectory))
+	}
+
+	defer os.RemoveAll(tempDirectory)
+
+	asset, err := NewReleaseAssetsWith(
+		ReleaseAssetsWithSourceTarFile(l.path),
+		ReleaseAssetsWithUntarFilePath(tempDirectory),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPackageInstallerFromFileName(filepath.Join(asset.DecompressPath(), DefaultPackageFile))
+}
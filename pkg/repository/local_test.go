@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalProviderArchive(t *testing.T) {
+	releaseDir, _ := os.MkdirTemp("", "temp-test-plugin-folder")
+	defer os.RemoveAll(releaseDir)
+
+	provider, err := NewLocalProvider("testdata/sourceTarFile.tar.gz")
+	require.Nil(t, err)
+
+	assert.Equal(t, LocalProviderName, provider.ProviderName())
+	assert.True(t, isUncacheable(provider))
+	assert.False(t, ServesDependencies(provider))
+
+	version, err := provider.Latest("rafaelcalleja", "assert.sh")
+	require.Nil(t, err)
+	assert.Equal(t, "v1.1", version)
+
+	versions, err := provider.List("rafaelcalleja", "assert.sh")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"v1.1"}, versions)
+
+	releaseVersion, err := NewReleaseLatestVersion("rafaelcalleja", "assert.sh", provider)
+	require.Nil(t, err)
+
+	require.Nil(t, releaseVersion.DownloadAndInstallAsset(provider, releaseDir))
+	assert.True(t, releaseVersion.IsVersionInstalled("v1.1", releaseDir))
+}
+
+func TestLocalProviderDirectory(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	releaseDir, _ := os.MkdirTemp("", "temp-test-plugin-folder")
+	defer os.RemoveAll(releaseDir)
+
+	asset := NewReleaseAssets("assert.sh", "v1.1", "testdata/sourceZipFile.zip", tempFolder)
+	require.Nil(t, os.MkdirAll(filepath.Join(asset.DecompressPath(), ".git"), 0755))
+
+	provider, err := NewLocalProvider(asset.DecompressPath())
+	require.Nil(t, err)
+
+	releaseVersion, err := NewReleaseLatestVersion("rafaelcalleja", "assert.sh", provider)
+	require.Nil(t, err)
+
+	downloaded, err := releaseVersion.DownloadAsset(provider, releaseDir)
+	require.Nil(t, err)
+	defer downloaded.Remove()
+
+	assert.Equal(t, "assert.sh-1.1", downloaded.PackageFolder())
+
+	_, err = os.Stat(filepath.Join(downloaded.DecompressPath(), ".git"))
+	assert.True(t, os.IsNotExist(err))
+
+	info, err := os.Stat(filepath.Join(downloaded.DecompressPath(), "tests.sh"))
+	require.Nil(t, err)
+	assert.NotEqual(t, os.FileMode(0), info.Mode().Perm()&0111)
+
+	require.Nil(t, releaseVersion.InstallAsset(downloaded, releaseDir))
+	assert.True(t, releaseVersion.IsVersionInstalled("v1.1", releaseDir))
+}
+
+func TestLocalProviderErrors(t *testing.T) {
+	_, err := NewLocalProvider("testdata/not_found.tar.gz")
+	assert.True(t, errors.Is(err, ErrLocalProviderPathNotFound))
+
+	provider, err := NewLocalProvider("testdata/packages_installed")
+	require.Nil(t, err)
+
+	_, err = provider.Latest("org", "name")
+	assert.NotNil(t, err)
+
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	require.Nil(t, os.WriteFile(filepath.Join(tempFolder, DefaultPackageFile), []byte("{\"name\":\"p\"}"), 0644))
+
+	provider, err = NewLocalProvider(tempFolder)
+	require.Nil(t, err)
+
+	_, err = provider.Latest("org", "name")
+	assert.True(t, errors.Is(err, ErrPackageVersionNotFound))
+}
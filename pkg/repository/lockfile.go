@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	DefaultLockFile        = "bpkg.lock"
+	CurrentLockfileVersion = 1
+)
+
+var (
+	ErrLockfileDigestMismatch = errors.New("lockfile digest mismatch")
+	ErrAmbiguousLockedPackage = errors.New("package installed from several hosts")
+)
+
+type LockedPackage struct {
+	Organization string `json:"organization"`
+	Name         string `json:"name"`
+	Alias        string `json:"alias,omitempty"`
+	Spec         string `json:"spec"`
+	Version      string `json:"version"`
+	Provider     string `json:"provider"`
+	Hostname     string `json:"hostname,omitempty"`
+	Remote       string `json:"remote,omitempty"`
+	IndexURL     string `json:"indexUrl,omitempty"`
+	Asset        string `json:"asset,omitempty"`
+	Sha256       string `json:"sha256,omitempty"`
+}
+
+// Lockfile packages are stored in a map so encoding/json writes them sorted by key
+type Lockfile struct {
+	LockfileVersion int                      `json:"lockfileVersion"`
+	Packages        map[string]LockedPackage `json:"packages"`
+}
+
+func NewLockfile() *Lockfile {
+	return &Lockfile{
+		LockfileVersion: CurrentLockfileVersion,
+		Packages:        make(map[string]LockedPackage),
+	}
+}
+
+// NewLockfileFromFileName returns an empty lockfile when filePath doesn't exist yet
+func NewLockfileFromFileName(filePath string) (*Lockfile, error) {
+	file, err := ioutil.ReadFile(filePath)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return NewLockfile(), nil
+	}
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Error can't open file %s", filePath))
+	}
+
+	lockfile := NewLockfile()
+	if err = json.Unmarshal(file, lockfile); err != nil {
+		return nil, errors.New(fmt.Sprintf("Error unmarsalling %s", filePath))
+	}
+
+	if nil == lockfile.Packages {
+		lockfile.Packages = make(map[string]LockedPackage)
+	}
+
+	return lockfile, nil
+}
+
+func LockfilePath(installPath string) string {
+	return filepath.Join(filepath.Dir(filepath.Clean(installPath)), DefaultLockFile)
+}
+
+// forgeDefaultHostnames are the hosts forge providers reach without a hostname, gitea and forgejo have none
+var forgeDefaultHostnames = map[string]string{
+	GithubProviderName:  GithubRepository,
+	GitlabProviderName:  GitlabRepository,
+	GiteaProviderName:   "",
+	ForgejoProviderName: "",
+}
+
+// PackageOrganization prefixes the organization with the hostname of forge providers reached on a non default host,
+// so github.com/org/name and ghe.corp.example/org/name get distinct lock keys and install directories
+func PackageOrganization(provider string, hostname string, organization string) string {
+	if "" == provider {
+		provider = GithubProviderName
+	}
+
+	defaultHostname, ok := forgeDefaultHostnames[provider]
+	if false == ok || "" == hostname || defaultHostname == hostname {
+		return organization
+	}
+
+	return fmt.Sprintf("%s/%s", hostname, organization)
+}
+
+func LockKey(organization string, name string) string {
+	return fmt.Sprintf("%s/%s", organization, name)
+}
+
+func (locked LockedPackage) Key() string {
+	organization := PackageOrganization(locked.Provider, locked.Hostname, locked.Organization)
+	if "" != locked.Alias {
+		return LockKey(organization, locked.Alias)
+	}
+
+	return LockKey(organization, locked.Name)
+}
+
+// InstallName is the directory name the package is installed at
+func (locked LockedPackage) InstallName() string {
+	organization := PackageOrganization(locked.Provider, locked.Hostname, locked.Organization)
+	if "" != locked.Alias {
+		return PackageInstallName(organization, locked.Alias)
+	}
+
+	return PackageInstallName(organization, locked.Name)
+}
+
+func (locked LockedPackage) Verify(sha256 string) error {
+	if "" == locked.Sha256 || locked.Sha256 == sha256 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s expected sha256 %s got %s", ErrLockfileDigestMismatch, locked.Key(), locked.Sha256, sha256)
+}
+
+func (lockfile *Lockfile) Get(key string) (LockedPackage, bool) {
+	locked, ok := lockfile.Packages[key]
+
+	return locked, ok
+}
+
+func (lockfile *Lockfile) GetByInstallName(installName string) (LockedPackage, bool) {
+	for _, locked := range lockfile.Packages {
+		if locked.InstallName() == installName {
+			return locked, true
+		}
+	}
+
+	return LockedPackage{}, false
+}
+
+// Find returns the package installed with the spec fqp, --provider and --hostname included. Specs with a scheme or a
+// hostname are looked up by their key first, any package of the organization with that name and provider is a match
+// otherwise as long as only one was installed
+func (lockfile *Lockfile) Find(fqp FullyQualifyPackage) (LockedPackage, error) {
+	locked, ok := lockfile.Get(LockKey(PackageOrganization(fqp.Scheme(), fqp.Hostname(), fqp.Organization()), fqp.Name()))
+	if ok && ("" == fqp.Scheme() || locked.Provider == fqp.Scheme()) {
+		return locked, nil
+	}
+
+	matches := make([]string, 0)
+	for key, locked := range lockfile.Packages {
+		name := locked.Name
+		if "" != locked.Alias {
+			name = locked.Alias
+		}
+
+		if locked.Organization != fqp.Organization() || name != fqp.Name() {
+			continue
+		}
+
+		if "" != fqp.Scheme() && locked.Provider != fqp.Scheme() {
+			continue
+		}
+
+		if "" != fqp.Hostname() && locked.Hostname != fqp.Hostname() {
+			continue
+		}
+
+		matches = append(matches, key)
+	}
+
+	switch len(matches) {
+	case 0:
+		return LockedPackage{}, fmt.Errorf("%w: %s", ErrPackageNotInstalled, fqp.String())
+	case 1:
+		return lockfile.Packages[matches[0]], nil
+	default:
+		sort.Strings(matches)
+
+		return LockedPackage{}, fmt.Errorf("%w: %s, name it with its host", ErrAmbiguousLockedPackage, strings.Join(matches, ", "))
+	}
+}
+
+func (lockfile *Lockfile) Put(locked LockedPackage) {
+	lockfile.Packages[locked.Key()] = locked
+}
+
+func (lockfile *Lockfile) Remove(key string) {
+	delete(lockfile.Packages, key)
+}
+
+func (lockfile *Lockfile) Write(filePath string) error {
+	content, err := json.MarshalIndent(lockfile, "", "  ")
+	if nil != err {
+		return err
+	}
+
+	content = append(content, '\n')
+
+	if err = os.MkdirAll(filepath.Dir(filePath), 0755); nil != err {
+		return errors.New(fmt.Sprintf("Error Creating dir %s", filepath.Dir(filePath)))
+	}
+
+	tmpPath := filePath + ".tmp"
+	if err = ioutil.WriteFile(tmpPath, content, 0644); nil != err {
+		return errors.New(fmt.Sprintf("Error Creating lockfile %s", tmpPath))
+	}
+
+	if err = os.Rename(tmpPath, filePath); nil != err {
+		return errors.New(fmt.Sprintf("Error Renaming lockfile from %s to %s", tmpPath, filePath))
+	}
+
+	return nil
+}
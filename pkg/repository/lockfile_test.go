@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLockfileReadWrite(t *testing.T) {
+	tempDir, _ := os.MkdirTemp("", "temp-test-lockfile-folder")
+	defer os.RemoveAll(tempDir)
+
+	lockFile := LockfilePath(filepath.Join(tempDir, "deps"))
+	assert.Equal(t, filepath.Join(tempDir, DefaultLockFile), lockFile)
+
+	lock, err := NewLockfileFromFileName(lockFile)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(lock.Packages))
+
+	lock.Put(LockedPackage{Organization: "zorg", Name: "b", Spec: "zorg/b:latest", Version: "v2.0", Provider: "github", Sha256: "bb"})
+	lock.Put(LockedPackage{Organization: "aorg", Name: "a", Spec: "aorg/a:v1.0", Version: "v1.0", Provider: "github", Sha256: "aa"})
+	lock.Put(LockedPackage{Organization: "aorg", Name: "a", Alias: "c", Spec: "aorg/a:v1.0", Version: "v1.0", Provider: "github", Sha256: "aa"})
+
+	require.Nil(t, lock.Write(lockFile))
+
+	content, err := ioutil.ReadFile(lockFile)
+	require.Nil(t, err)
+	assert.True(t, strings.HasSuffix(string(content), "}\n"))
+	assert.True(t, strings.Index(string(content), "aorg/a") < strings.Index(string(content), "aorg/c"))
+	assert.True(t, strings.Index(string(content), "aorg/c") < strings.Index(string(content), "zorg/b"))
+
+	other, err := NewLockfileFromFileName(lockFile)
+	require.Nil(t, err)
+	assert.Equal(t, lock, other)
+
+	locked, ok := other.Get("zorg/b")
+	require.True(t, ok)
+	assert.Equal(t, "v2.0", locked.Version)
+
+	other.Remove("zorg/b")
+	_, ok = other.Get("zorg/b")
+	assert.False(t, ok)
+
+	require.Nil(t, other.Write(lockFile))
+	again, err := ioutil.ReadFile(lockFile)
+	require.Nil(t, err)
+	assert.NotEqual(t, content, again)
+
+	_, err = NewLockfileFromFileName("testdata/invalid.json")
+	assert.NotNil(t, err)
+}
+
+func TestLockedPackageVerify(t *testing.T) {
+	locked := LockedPackage{Organization: "org", Name: "name", Sha256: "aa"}
+
+	assert.Nil(t, locked.Verify("aa"))
+	assert.True(t, errors.Is(locked.Verify("bb"), ErrLockfileDigestMismatch))
+
+	locked.Sha256 = ""
+	assert.Nil(t, locked.Verify("bb"))
+}
+
+func TestLockedPackageHyphenatedOrganization(t *testing.T) {
+	lock := NewLockfile()
+	lock.Put(LockedPackage{Organization: "my-org", Name: "name", Provider: GithubProviderName, Hostname: GithubRepository})
+
+	// installs made before hostnames and nested groups were escaped keep their directory
+	locked, ok := lock.GetByInstallName("my-org-name")
+	require.True(t, ok)
+	assert.Equal(t, "my-org/name", locked.Key())
+}
+
+func TestLockedPackageHostname(t *testing.T) {
+	public := LockedPackage{Organization: "org", Name: "name", Provider: GithubProviderName, Hostname: GithubRepository}
+	enterprise := LockedPackage{Organization: "org", Name: "name", Provider: GithubProviderName, Hostname: "ghe.corp.example"}
+
+	assert.Equal(t, "org/name", public.Key())
+	assert.Equal(t, "ghe.corp.example/org/name", enterprise.Key())
+	assert.NotEqual(t, public.InstallName(), enterprise.InstallName())
+
+	gitlab := LockedPackage{Organization: "group/sub", Name: "name", Alias: "alias", Provider: GitlabProviderName, Hostname: "gitlab.corp.example"}
+	assert.Equal(t, "gitlab.corp.example/group/sub/alias", gitlab.Key())
+	assert.Equal(t, "gitlab.corp.example%2Fgroup%2Fsub-alias", gitlab.InstallName())
+
+	gitlab.Hostname = GitlabRepository
+	assert.Equal(t, "group/sub/alias", gitlab.Key())
+
+	assert.Equal(t, "org", PackageOrganization(GitProviderName, "git.corp.example", "org"))
+	assert.Equal(t, "gitea.corp.example/org", PackageOrganization(GiteaProviderName, "gitea.corp.example", "org"))
+	assert.Equal(t, "org", PackageOrganization("", "", "org"))
+}
+
+func TestLockfileFind(t *testing.T) {
+	lock := NewLockfile()
+	lock.Put(LockedPackage{Organization: "group", Name: "name", Version: "v1.0", Provider: GitlabProviderName, Hostname: "gitlab.corp"})
+
+	spec, _ := NewFullyQualifyPackage("group/name:v1.0")
+	locked, err := lock.Find(spec)
+	require.Nil(t, err)
+	assert.Equal(t, "gitlab.corp/group/name", locked.Key())
+
+	spec, _ = NewFullyQualifyPackage("gitlab://gitlab.corp/group/name:v1.0")
+	_, err = lock.Find(spec)
+	assert.Nil(t, err)
+
+	spec, _ = NewFullyQualifyPackage("gitea://gitea.corp/group/name:v1.0")
+	_, err = lock.Find(spec)
+	assert.True(t, errors.Is(err, ErrPackageNotInstalled))
+
+	lock.Put(LockedPackage{Organization: "group", Name: "name", Version: "v1.0", Provider: GithubProviderName, Hostname: GithubRepository})
+
+	spec, _ = NewFullyQualifyPackage("group/name:v1.0")
+	locked, err = lock.Find(spec)
+	require.Nil(t, err)
+	assert.Equal(t, GithubProviderName, locked.Provider)
+
+	spec, _ = NewFullyQualifyPackage("gitlab:group/name:v1.0")
+	locked, err = lock.Find(spec)
+	require.Nil(t, err)
+	assert.Equal(t, "gitlab.corp", locked.Hostname)
+
+	lock.Remove("group/name")
+	lock.Put(LockedPackage{Organization: "group", Name: "name", Version: "v1.0", Provider: GiteaProviderName, Hostname: "gitea.corp"})
+
+	spec, _ = NewFullyQualifyPackage("group/name:v1.0")
+	_, err = lock.Find(spec)
+	assert.True(t, errors.Is(err, ErrAmbiguousLockedPackage))
+
+	spec, _ = NewFullyQualifyPackage("gitea:group/name:v1.0")
+	locked, err = lock.Find(spec)
+	require.Nil(t, err)
+	assert.Equal(t, "gitea.corp/group/name", locked.Key())
+}
+
+func TestReleaseAssetsSha256(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	asset := NewReleaseAssets("testName", "v1.1", "testdata/sourceTarFile.tar.gz", tempFolder)
+
+	sha256, err := asset.Sha256()
+	require.Nil(t, err)
+
+	expected, err := FileSha256("testdata/sourceTarFile.tar.gz")
+	require.Nil(t, err)
+
+	assert.Equal(t, expected, sha256)
+	assert.Equal(t, 64, len(sha256))
+
+	_, err = FileSha256("testdata/not_found.tar.gz")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	MirrorProviderName = "mirror"
+	MirrorIndexFile    = "index.json"
+)
+
+var (
+	ErrMirrorNotSet           = errors.New("mirror provider requires a mirror dir or file:// url")
+	ErrMirrorIndexNotFound    = errors.New("mirror index not found")
+	ErrMirrorReleaseNotFound  = errors.New("release not mirrored")
+	ErrMirrorChecksumMismatch = errors.New("mirrored file doesn't match its sha256")
+	ErrMirrorLocationNotLocal = errors.New("mirror url must be a local file:// url")
+)
+
+func init() {
+	RegisterProvider(MirrorProviderName, newMirrorProviders)
+}
+
+// MirroredRelease File and Signature are slash separated paths relative to the mirror dir, Asset is the pattern of a
+// prebuilt release asset, empty for source archives
+type MirroredRelease struct {
+	Version    string    `json:"version"`
+	File       string    `json:"file"`
+	Sha256     string    `json:"sha256"`
+	Signature  string    `json:"signature,omitempty"`
+	Provider   string    `json:"provider,omitempty"`
+	Hostname   string    `json:"hostname,omitempty"`
+	Asset      string    `json:"asset,omitempty"`
+	MirroredAt time.Time `json:"mirroredAt"`
+}
+
+// MirrorIndex lists the releases of a mirror dir by package, <org>/<name> keys as in the lockfile, so organizations of
+// forges on a non default host are prefixed with it
+type MirrorIndex struct {
+	Packages map[string][]MirroredRelease `json:"packages"`
+	dir      string
+}
+
+// NewMirrorIndexFromDir returns an empty index when the dir has none yet
+func NewMirrorIndexFromDir(dir string) (*MirrorIndex, error) {
+	index := &MirrorIndex{Packages: make(map[string][]MirroredRelease), dir: dir}
+
+	indexFile := filepath.Join(dir, MirrorIndexFile)
+
+	content, err := ioutil.ReadFile(indexFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	}
+
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Error can't open file %s", indexFile))
+	}
+
+	if err = json.Unmarshal(content, index); err != nil {
+		return nil, errors.New(fmt.Sprintf("Error unmarsalling %s", indexFile))
+	}
+
+	if nil == index.Packages {
+		index.Packages = make(map[string][]MirroredRelease)
+	}
+
+	return index, nil
+}
+
+func (index *MirrorIndex) Dir() string {
+	return index.dir
+}
+
+func (index *MirrorIndex) Write() error {
+	content, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(index.dir, 0755); err != nil {
+		return errors.New(fmt.Sprintf("Error can't create mirror dir %s", index.dir))
+	}
+
+	return ioutil.WriteFile(filepath.Join(index.dir, MirrorIndexFile), content, 0644)
+}
+
+// Releases returns the mirrored releases of the package newest first
+func (index *MirrorIndex) Releases(organization string, name string) []MirroredRelease {
+	return index.Packages[LockKey(organization, name)]
+}
+
+func (index *MirrorIndex) Release(organization string, name string, version string) (MirroredRelease, bool) {
+	for _, release := range index.Releases(organization, name) {
+		if release.Version == version {
+			return release, true
+		}
+	}
+
+	return MirroredRelease{}, false
+}
+
+// Put replaces the release with the same version and keeps semantic versions sorted newest first
+func (index *MirrorIndex) Put(organization string, name string, release MirroredRelease) {
+	key := LockKey(organization, name)
+
+	releases := []MirroredRelease{release}
+	for _, mirrored := range index.Packages[key] {
+		if mirrored.Version != release.Version {
+			releases = append(releases, mirrored)
+		}
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		left, leftErr := NewSemanticVersion(releases[i].Version)
+		right, rightErr := NewSemanticVersion(releases[j].Version)
+		if leftErr != nil || rightErr != nil {
+			return false
+		}
+
+		return left.Compare(right) > 0
+	})
+
+	index.Packages[key] = releases
+}
+
+// Mirror downloads releaseVersion from provider into <dir>/<org>/<name>/<version>, org being prefixed like the keys, with its signature when the
+// provider publishes one, and records it in the index without writing it
+func (index *MirrorIndex) Mirror(provider ReleasesProvider, releaseVersion *ReleaseVersion) (MirroredRelease, error) {
+	tempDirectory, err := os.MkdirTemp("", "temp-mirror-folder")
+	if err != nil {
+		return MirroredRelease{}, errors.New(fmt.Sprintf("Error Creating temporal dir %s", tempDirectory))
+	}
+
+	defer os.RemoveAll(tempDirectory)
+
+	downloaded, err := releaseVersion.download(provider, tempDirectory)
+	if err != nil {
+		return MirroredRelease{}, err
+	}
+
+	providerName, hostname := DescribeProvider(provider)
+	organization := PackageOrganization(providerName, hostname, releaseVersion.Organization)
+
+	releaseDir := path.Join(url.PathEscape(organization), url.PathEscape(releaseVersion.Name),
+		url.PathEscape(releaseVersion.Version()))
+
+	targetDir := filepath.Join(index.dir, filepath.FromSlash(releaseDir))
+	_ = os.RemoveAll(targetDir)
+	if err = os.MkdirAll(targetDir, 0755); err != nil {
+		return MirroredRelease{}, errors.New(fmt.Sprintf("Error can't create mirror dir %s", targetDir))
+	}
+
+	fileName := filepath.Base(downloaded)
+	if _, err = copyCacheFile(downloaded, filepath.Join(targetDir, fileName)); err != nil {
+		return MirroredRelease{}, err
+	}
+
+	release := MirroredRelease{
+		Version:    releaseVersion.Version(),
+		File:       path.Join(releaseDir, fileName),
+		Provider:   providerName,
+		Hostname:   hostname,
+		Asset:      releaseAssetPattern(provider, releaseVersion),
+		MirroredAt: time.Now().UTC(),
+	}
+
+	if release.Sha256, err = FileSha256(downloaded); err != nil {
+		return MirroredRelease{}, err
+	}
+
+	if signaturesProvider, ok := provider.(SignaturesProvider); ok {
+		err = signaturesProvider.DownloadSignature(releaseVersion, fileName, targetDir)
+		if err == nil {
+			release.Signature = release.File + SignatureExtension
+		} else if false == errors.Is(err, ErrSignatureNotFound) {
+			return MirroredRelease{}, err
+		}
+	}
+
+	index.Put(organization, releaseVersion.Name, release)
+
+	return release, nil
+}
+
+// MirrorProvider serves the releases of a mirror dir written by the mirror command, packages are looked up on hostname
+// first when it is set
+type MirrorProvider struct {
+	dir      string
+	hostname string
+	index    *MirrorIndex
+}
+
+// NewMirrorProvider takes the mirror dir as a path or a file:// url
+func NewMirrorProvider(location string, options ...func(*MirrorProvider) error) (*MirrorProvider, error) {
+	dir, err := mirrorDir(location)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = os.Stat(filepath.Join(dir, MirrorIndexFile)); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMirrorIndexNotFound, filepath.Join(dir, MirrorIndexFile))
+	}
+
+	index, err := NewMirrorIndexFromDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrorProvider := &MirrorProvider{dir: dir, index: index}
+	for _, option := range options {
+		if err = option(mirrorProvider); err != nil {
+			return nil, err
+		}
+	}
+
+	return mirrorProvider, nil
+}
+
+// MirrorWithHostname picks the releases mirrored from hostname, e.g. ghe.corp.example, over those of the default host
+func MirrorWithHostname(hostname string) func(*MirrorProvider) error {
+	return func(m *MirrorProvider) error {
+		m.hostname = hostname
+		return nil
+	}
+}
+
+func newMirrorProviders(options ProviderOptions) (ReleasesProvider, ReleaseVersionFinder, error) {
+	provider, err := NewMirrorProvider(options.Remote, MirrorWithHostname(options.Hostname))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return provider, provider, nil
+}
+
+func mirrorDir(location string) (string, error) {
+	if "" == strings.TrimSpace(location) {
+		return "", ErrMirrorNotSet
+	}
+
+	if false == strings.HasPrefix(location, "file://") {
+		return location, nil
+	}
+
+	parsed, err := url.Parse(location)
+	if err != nil || ("" != parsed.Host && "localhost" != parsed.Host) {
+		return "", fmt.Errorf("%w: %s", ErrMirrorLocationNotLocal, location)
+	}
+
+	return filepath.FromSlash(parsed.Path), nil
+}
+
+func (m *MirrorProvider) ProviderName() string {
+	return MirrorProviderName
+}
+
+func (m *MirrorProvider) Hostname() string {
+	return ""
+}
+
+// ServesDependencies is true, mirrors are used by hosts that can't reach the providers of the dependencies
+func (m *MirrorProvider) ServesDependencies() bool {
+	return true
+}
+
+// Uncacheable is true, the mirror is already a local copy of the releases
+func (m *MirrorProvider) Uncacheable() bool {
+	return true
+}
+
+func (m *MirrorProvider) Dir() string {
+	return m.dir
+}
+
+func (m *MirrorProvider) DownloadVariant() string {
+	return m.dir
+}
+
+// releases are those mirrored from hostname, otherwise those keyed without a host
+func (m *MirrorProvider) releases(organization string, name string) []MirroredRelease {
+	if "" != m.hostname {
+		if releases := m.index.Releases(fmt.Sprintf("%s/%s", m.hostname, organization), name); len(releases) > 0 {
+			return releases
+		}
+	}
+
+	return m.index.Releases(organization, name)
+}
+
+func (m *MirrorProvider) release(releaseVersion *ReleaseVersion) (MirroredRelease, error) {
+	for _, release := range m.releases(releaseVersion.Organization, releaseVersion.Name) {
+		if release.Version == releaseVersion.Version() {
+			return release, nil
+		}
+	}
+
+	return MirroredRelease{}, fmt.Errorf("%w: %s:%s in %s", ErrMirrorReleaseNotFound,
+		LockKey(releaseVersion.Organization, releaseVersion.Name), releaseVersion.Version(), m.dir)
+}
+
+// ReleaseAssetPattern is the asset pattern the release was mirrored with, so prebuilt binaries install as such
+func (m *MirrorProvider) ReleaseAssetPattern(releaseVersion *ReleaseVersion) string {
+	release, err := m.release(releaseVersion)
+	if err != nil {
+		return ""
+	}
+
+	return release.Asset
+}
+
+// Download copies the mirrored file after checking it against the sha256 of the index
+func (m *MirrorProvider) Download(releaseVersion *ReleaseVersion, downloadDir string) error {
+	release, err := m.release(releaseVersion)
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(m.dir, filepath.FromSlash(release.File))
+
+	sha256, err := FileSha256(filePath)
+	if err != nil {
+		return err
+	}
+
+	if sha256 != release.Sha256 {
+		return fmt.Errorf("%w: %s", ErrMirrorChecksumMismatch, filePath)
+	}
+
+	_, err = copyCacheFile(filePath, filepath.Join(downloadDir, path.Base(release.File)))
+
+	return err
+}
+
+func (m *MirrorProvider) DownloadSignature(releaseVersion *ReleaseVersion, assetName string, downloadDir string) error {
+	release, err := m.release(releaseVersion)
+	if err != nil {
+		return err
+	}
+
+	if "" == release.Signature || assetName+SignatureExtension != path.Base(release.Signature) {
+		return fmt.Errorf("%w: %s%s", ErrSignatureNotFound, assetName, SignatureExtension)
+	}
+
+	_, err = copyCacheFile(filepath.Join(m.dir, filepath.FromSlash(release.Signature)),
+		filepath.Join(downloadDir, path.Base(release.Signature)))
+
+	return err
+}
+
+// Latest is the highest mirrored stable version, otherwise the newest mirrored release
+func (m *MirrorProvider) Latest(organization string, name string) (string, error) {
+	versions, err := m.List(organization, name)
+	if err != nil {
+		return "", err
+	}
+
+	constraint, _ := NewVersionConstraint(latestStableVersion)
+	if latest, err := constraint.Highest(versions); err == nil {
+		return latest, nil
+	}
+
+	if 0 == len(versions) {
+		return "", fmt.Errorf("%w: %s/%s", ErrNoMatchingReleaseVersion, organization, name)
+	}
+
+	return versions[0], nil
+}
+
+func (m *MirrorProvider) List(organization string, name string) ([]string, error) {
+	versions := make([]string, 0)
+	for _, release := range m.releases(organization, name) {
+		versions = append(versions, release.Version)
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestMirror(t *testing.T, dir string) *MirrorIndex {
+	archive, err := os.ReadFile("testdata/sourceTarFile.tar.gz")
+	require.Nil(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, SignatureExtension) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+
+	provider, err := NewHttpProvider(server.URL+"/{{.Name}}-{{.Version}}.tar.gz", "")
+	require.Nil(t, err)
+
+	index, err := NewMirrorIndexFromDir(dir)
+	require.Nil(t, err)
+
+	for _, version := range []string{"v1.0", "v1.2", "v2.0-rc.1", "v1.1"} {
+		releaseVersion := NewReleaseVersion("rafaelcalleja", "assert.sh", version)
+		_, err = index.Mirror(provider, &releaseVersion)
+		require.Nil(t, err)
+	}
+
+	signed := &mockSignaturesReleasesProvider{
+		mockReleasesProvider: &mockReleasesProvider{DownloadFn: func(releaseVersion *ReleaseVersion, downloadDir string) error {
+			_, err := copyCacheFile("testdata/sourceTarFile.tar.gz", filepath.Join(downloadDir, "signed.tar.gz"))
+
+			return err
+		}},
+		signature: []byte("signature"),
+	}
+
+	releaseVersion := NewReleaseVersion("group/sub", "signed", "v3.0")
+	release, err := index.Mirror(signed, &releaseVersion)
+	require.Nil(t, err)
+	assert.Equal(t, "group%2Fsub/signed/v3.0/signed.tar.gz", release.File)
+	assert.Equal(t, "group%2Fsub/signed/v3.0/signed.tar.gz.sig", release.Signature)
+
+	require.Nil(t, index.Write())
+
+	return index
+}
+
+func TestMirrorIndex(t *testing.T) {
+	mirrorDir, _ := os.MkdirTemp("", "temp-test-mirror-folder")
+	defer os.RemoveAll(mirrorDir)
+
+	newTestMirror(t, mirrorDir)
+
+	index, err := NewMirrorIndexFromDir(mirrorDir)
+	require.Nil(t, err)
+
+	releases := index.Releases("rafaelcalleja", "assert.sh")
+	require.Len(t, releases, 4)
+	assert.Equal(t, "v2.0-rc.1", releases[0].Version)
+	assert.Equal(t, "v1.0", releases[3].Version)
+	assert.Equal(t, HttpProviderName, releases[0].Provider)
+	assert.Empty(t, releases[0].Signature)
+	assert.FileExists(t, filepath.Join(mirrorDir, filepath.FromSlash(releases[0].File)))
+
+	sha256, _ := FileSha256("testdata/sourceTarFile.tar.gz")
+	assert.Equal(t, sha256, releases[0].Sha256)
+
+	_, err = NewMirrorProvider("")
+	assert.True(t, errors.Is(err, ErrMirrorNotSet))
+
+	_, err = NewMirrorProvider(filepath.Join(mirrorDir, "missing"))
+	assert.True(t, errors.Is(err, ErrMirrorIndexNotFound))
+
+	_, err = NewMirrorProvider("file://remote.example/mirror")
+	assert.True(t, errors.Is(err, ErrMirrorLocationNotLocal))
+}
+
+func TestMirrorProvider(t *testing.T) {
+	mirrorDir, _ := os.MkdirTemp("", "temp-test-mirror-folder")
+	defer os.RemoveAll(mirrorDir)
+
+	newTestMirror(t, mirrorDir)
+
+	provider, finder, err := NewProviders(MirrorProviderName, ProviderOptions{Remote: "file://" + filepath.ToSlash(mirrorDir)})
+	require.Nil(t, err)
+	assert.True(t, ServesDependencies(provider))
+	assert.True(t, isUncacheable(provider))
+
+	latest, err := finder.Latest("group/sub", "signed")
+	require.Nil(t, err)
+	assert.Equal(t, "v3.0", latest)
+
+	mirror := provider.(*MirrorProvider)
+
+	downloadDir, _ := os.MkdirTemp("", "temp-test-download-folder")
+	defer os.RemoveAll(downloadDir)
+
+	signed := NewReleaseVersion("group/sub", "signed", "v3.0")
+	require.Nil(t, mirror.DownloadSignature(&signed, "signed.tar.gz", downloadDir))
+	assert.FileExists(t, filepath.Join(downloadDir, "signed.tar.gz.sig"))
+
+	missing := NewReleaseVersion("rafaelcalleja", "assert.sh", "v9.9")
+	err = mirror.Download(&missing, downloadDir)
+	assert.True(t, errors.Is(err, ErrMirrorReleaseNotFound))
+
+	unsigned := NewReleaseVersion("rafaelcalleja", "assert.sh", "v1.1")
+	release, _ := mirror.index.Release("rafaelcalleja", "assert.sh", "v1.1")
+	require.Nil(t, os.WriteFile(filepath.Join(mirrorDir, filepath.FromSlash(release.File)), []byte("tampered"), 0644))
+
+	err = mirror.Download(&unsigned, downloadDir)
+	assert.True(t, errors.Is(err, ErrMirrorChecksumMismatch))
+}
+
+type testMirroredProvider struct {
+	*mockReleasesProvider
+	hostname string
+	asset    string
+}
+
+func (p *testMirroredProvider) ProviderName() string {
+	return GithubProviderName
+}
+
+func (p *testMirroredProvider) Hostname() string {
+	return p.hostname
+}
+
+func (p *testMirroredProvider) AssetPattern() string {
+	return p.asset
+}
+
+func newTestMirroredProvider(hostname string, asset string, file string, body string) *testMirroredProvider {
+	return &testMirroredProvider{
+		mockReleasesProvider: &mockReleasesProvider{DownloadFn: func(releaseVersion *ReleaseVersion, downloadDir string) error {
+			return os.WriteFile(filepath.Join(downloadDir, file), []byte(body), 0644)
+		}},
+		hostname: hostname,
+		asset:    asset,
+	}
+}
+
+func TestMirrorIndexHostnamesAndAssets(t *testing.T) {
+	mirrorDir := t.TempDir()
+
+	index, err := NewMirrorIndexFromDir(mirrorDir)
+	require.Nil(t, err)
+
+	releaseVersion := NewReleaseVersion("org", "tool", "v1.0")
+	for _, provider := range []*testMirroredProvider{
+		newTestMirroredProvider(GithubRepository, "tool_linux", "tool_linux", "#!/bin/sh\necho github"),
+		newTestMirroredProvider("ghe.corp.example", "tool_linux", "tool_linux", "#!/bin/sh\necho enterprise"),
+	} {
+		release, err := index.Mirror(provider, &releaseVersion)
+		require.Nil(t, err)
+		assert.Equal(t, "tool_linux", release.Asset)
+	}
+
+	require.Nil(t, index.Write())
+
+	// the same organization and name on two hosts don't overwrite each other
+	require.Len(t, index.Releases("org", "tool"), 1)
+	require.Len(t, index.Releases("ghe.corp.example/org", "tool"), 1)
+	assert.NotEqual(t, index.Releases("org", "tool")[0].File, index.Releases("ghe.corp.example/org", "tool")[0].File)
+
+	for hostname, expected := range map[string]string{"": "github", GithubRepository: "github", "ghe.corp.example": "enterprise"} {
+		provider, _, err := NewProviders(MirrorProviderName, ProviderOptions{Remote: mirrorDir, Hostname: hostname})
+		require.Nil(t, err)
+
+		// prebuilt single binary releases keep their prebuilt handling
+		asset, err := releaseVersion.DownloadCachedAsset(provider, mirrorDir, nil)
+		require.Nil(t, err, hostname)
+
+		content, err := os.ReadFile(filepath.Join(asset.DecompressPath(), "tool"))
+		require.Nil(t, err, hostname)
+		assert.Contains(t, string(content), expected, hostname)
+		require.Nil(t, asset.Remove())
+	}
+}
@@ -1,7 +1,14 @@
 package repository
 
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
 type mockReleaseVersionFinder struct {
 	LatestFn func(organization string, name string) (string, error)
+	ListFn   func(organization string, name string) ([]string, error)
 }
 
 func newMockReleaseVersionFinder() *mockReleaseVersionFinder {
@@ -11,3 +18,53 @@ func newMockReleaseVersionFinder() *mockReleaseVersionFinder {
 func (m *mockReleaseVersionFinder) Latest(organization string, name string) (string, error) {
 	return m.LatestFn(organization, name)
 }
+
+func (m *mockReleaseVersionFinder) List(organization string, name string) ([]string, error) {
+	return m.ListFn(organization, name)
+}
+
+type mockReleasesProvider struct {
+	DownloadFn func(releaseVersion *ReleaseVersion, downloadDir string) error
+}
+
+func newMockReleasesProvider() *mockReleasesProvider {
+	return &mockReleasesProvider{DownloadFn: writeMockFiles()}
+}
+
+func (m *mockReleasesProvider) Download(releaseVersion *ReleaseVersion, downloadDir string) error {
+	return m.DownloadFn(releaseVersion, downloadDir)
+}
+
+type mockChecksumsReleasesProvider struct {
+	*mockReleasesProvider
+	DownloadChecksumsFn func(releaseVersion *ReleaseVersion, downloadDir string) error
+}
+
+func (m *mockChecksumsReleasesProvider) DownloadChecksums(releaseVersion *ReleaseVersion, downloadDir string) error {
+	return m.DownloadChecksumsFn(releaseVersion, downloadDir)
+}
+
+type mockSignaturesReleasesProvider struct {
+	*mockReleasesProvider
+	signature []byte
+}
+
+func (m *mockSignaturesReleasesProvider) DownloadSignature(releaseVersion *ReleaseVersion, assetName string, downloadDir string) error {
+	if nil == m.signature {
+		return fmt.Errorf("%w: %s", ErrSignatureNotFound, assetName)
+	}
+
+	return os.WriteFile(filepath.Join(downloadDir, assetName+SignatureExtension), m.signature, 0644)
+}
+
+func writeMockFiles(files ...string) func(*ReleaseVersion, string) error {
+	return func(releaseVersion *ReleaseVersion, downloadDir string) error {
+		for _, file := range files {
+			if err := os.WriteFile(filepath.Join(downloadDir, file), []byte(file), 0644); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"sync"
+)
+
+const (
+	DefaultOutdatedConcurrency = 4
+
+	// UnknownVersion is the wanted and latest version of packages missing from the lockfile
+	UnknownVersion = "unknown"
+)
+
+// OutdatedPackage InstallName is only set for packages missing from the lockfile, which have no Package entry
+type OutdatedPackage struct {
+	Package     LockedPackage
+	InstallName string
+	Current     string
+	Wanted      string
+	Latest      string
+	Err         error
+}
+
+// Name is the lock key of the package, or its install directory when it isn't locked
+func (outdated OutdatedPackage) Name() string {
+	if outdated.IsUnlocked() {
+		return outdated.InstallName
+	}
+
+	return outdated.Package.Key()
+}
+
+// IsUnlocked is true for packages installed before the lockfile existed, their organization and provider are unknown
+// so their releases can't be looked up
+func (outdated OutdatedPackage) IsUnlocked() bool {
+	return "" != outdated.InstallName
+}
+
+// IsBehind compares versions semantically when both are semantic versions and textually otherwise
+func (outdated OutdatedPackage) IsBehind() bool {
+	if nil != outdated.Err || "" == outdated.Latest || outdated.IsUnlocked() {
+		return false
+	}
+
+	current, err := NewSemanticVersion(outdated.Current)
+	if err != nil {
+		return outdated.Current != outdated.Latest
+	}
+
+	latest, err := NewSemanticVersion(outdated.Latest)
+	if err != nil {
+		return outdated.Current != outdated.Latest
+	}
+
+	return latest.Compare(current) > 0
+}
+
+// CheckOutdated looks up the latest and wanted version of every package with at most concurrency lookups in flight,
+// results keep the order of packages
+func CheckOutdated(packages []LockedPackage, finder ReleaseVersionFinder, concurrency int) []OutdatedPackage {
+	return CheckOutdatedWith(packages, func(LockedPackage) (ReleaseVersionFinder, error) {
+		return finder, nil
+	}, concurrency)
+}
+
+// CheckOutdatedInstalled checks every package installed at installPath with the finder of its lockfile entry, packages
+// missing from the lockfile are reported after them with unknown wanted and latest versions
+func CheckOutdatedInstalled(
+	installPath string,
+	lock *Lockfile,
+	finders func(LockedPackage) (ReleaseVersionFinder, error),
+	concurrency int,
+) ([]OutdatedPackage, error) {
+	installed, err := InstalledPackages(installPath)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := make([]LockedPackage, 0, len(installed))
+	unlocked := make([]OutdatedPackage, 0)
+	for _, pkg := range installed {
+		if lockedPackage, ok := lock.GetByInstallName(pkg.Name); ok {
+			locked = append(locked, lockedPackage)
+
+			continue
+		}
+
+		unlocked = append(unlocked, OutdatedPackage{
+			InstallName: pkg.Name,
+			Current:     pkg.Version,
+			Wanted:      UnknownVersion,
+			Latest:      UnknownVersion,
+		})
+	}
+
+	return append(CheckOutdatedWith(locked, finders, concurrency), unlocked...), nil
+}
+
+// CheckOutdatedWith asks finders for the finder of each package, e.g. one per locked provider and hostname
+func CheckOutdatedWith(
+	packages []LockedPackage,
+	finders func(LockedPackage) (ReleaseVersionFinder, error),
+	concurrency int,
+) []OutdatedPackage {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]OutdatedPackage, len(packages))
+	semaphore := make(chan struct{}, concurrency)
+	wg := new(sync.WaitGroup)
+
+	for i, locked := range packages {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(i int, locked LockedPackage) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			finder, err := finders(locked)
+			if err != nil {
+				results[i] = OutdatedPackage{Package: locked, Current: locked.Version, Wanted: locked.Version, Err: err}
+
+				return
+			}
+
+			results[i] = checkOutdated(locked, finder)
+		}(i, locked)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func checkOutdated(locked LockedPackage, finder ReleaseVersionFinder) OutdatedPackage {
+	outdated := OutdatedPackage{Package: locked, Current: locked.Version, Wanted: locked.Version}
+
+	latest, err := NewReleaseLatestVersion(locked.Organization, locked.Name, finder)
+	if err != nil {
+		outdated.Err = err
+
+		return outdated
+	}
+
+	outdated.Latest = latest.Version()
+
+	fqp, err := NewFullyQualifyPackage(locked.Spec)
+	if err != nil {
+		outdated.Err = err
+
+		return outdated
+	}
+
+	switch {
+	case "latest" == fqp.Version():
+		outdated.Wanted = outdated.Latest
+	case fqp.IsVersionConstraint():
+		wanted, err := NewReleaseMatchingVersion(locked.Organization, locked.Name, fqp.Version(), finder)
+		if err != nil {
+			outdated.Err = err
+
+			return outdated
+		}
+
+		outdated.Wanted = wanted.Version()
+	default:
+		outdated.Wanted = fqp.Version()
+	}
+
+	return outdated
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckOutdated(t *testing.T) {
+	finder := newMockReleaseVersionFinder()
+
+	var inFlight, maxInFlight int32
+	latest := map[string]string{"a": "v1.2.0", "b": "v2.1.0", "c": "v1.0.0", "d": ""}
+	mutex := new(sync.Mutex)
+
+	finder.LatestFn = func(organization string, name string) (string, error) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		mutex.Lock()
+		if current > maxInFlight {
+			maxInFlight = current
+		}
+		mutex.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		if "" == latest[name] {
+			return "", errors.New("not found")
+		}
+
+		return latest[name], nil
+	}
+
+	finder.ListFn = func(organization string, name string) ([]string, error) {
+		return []string{"v1.0.0", "v1.1.0", "v1.2.0", "v2.0.0", "v2.1.0"}, nil
+	}
+
+	packages := []LockedPackage{
+		{Organization: "org", Name: "a", Spec: "org/a:^1.0", Version: "v1.0.0"},
+		{Organization: "org", Name: "b", Spec: "org/b:latest", Version: "v2.0.0"},
+		{Organization: "org", Name: "c", Spec: "org/c:v1.0.0", Version: "v1.0.0"},
+		{Organization: "org", Name: "d", Spec: "org/d:v1.0.0", Version: "v1.0.0"},
+	}
+
+	results := CheckOutdated(append(packages, packages...), finder, 2)
+
+	assert.LessOrEqual(t, maxInFlight, int32(2))
+	assert.Equal(t, 8, len(results))
+
+	assert.Equal(t, packages[0], results[0].Package)
+	assert.Equal(t, "v1.0.0", results[0].Current)
+	assert.Equal(t, "v1.2.0", results[0].Wanted)
+	assert.Equal(t, "v1.2.0", results[0].Latest)
+	assert.True(t, results[0].IsBehind())
+
+	assert.Equal(t, "v2.1.0", results[1].Wanted)
+	assert.True(t, results[1].IsBehind())
+
+	assert.Equal(t, "v1.0.0", results[2].Wanted)
+	assert.False(t, results[2].IsBehind())
+
+	assert.NotNil(t, results[3].Err)
+	assert.False(t, results[3].IsBehind())
+
+	assert.Equal(t, results[:4], results[4:])
+}
+
+func TestCheckOutdatedWithHostFinders(t *testing.T) {
+	finders := map[string]*mockReleaseVersionFinder{}
+	for _, hostname := range []string{GithubRepository, "ghe.corp.example"} {
+		latest := "v1.0.0"
+		if GithubRepository != hostname {
+			latest = "v2.0.0"
+		}
+
+		finder := newMockReleaseVersionFinder()
+		finder.LatestFn = func(organization string, name string) (string, error) {
+			return latest, nil
+		}
+		finder.ListFn = func(organization string, name string) ([]string, error) {
+			return []string{latest}, nil
+		}
+
+		finders[hostname] = finder
+	}
+
+	packages := []LockedPackage{
+		{Organization: "org", Name: "a", Spec: "org/a:latest", Version: "v1.0.0", Hostname: GithubRepository},
+		{Organization: "org", Name: "a", Spec: "ghe.corp.example/org/a:latest", Version: "v1.0.0", Hostname: "ghe.corp.example"},
+		{Organization: "org", Name: "b", Spec: "unknown.example/org/b:latest", Version: "v1.0.0", Hostname: "unknown.example"},
+	}
+
+	results := CheckOutdatedWith(packages, func(locked LockedPackage) (ReleaseVersionFinder, error) {
+		if finder, ok := finders[locked.Hostname]; ok {
+			return finder, nil
+		}
+
+		return nil, errors.New("unknown host")
+	}, 2)
+
+	assert.Equal(t, "v1.0.0", results[0].Wanted)
+	assert.False(t, results[0].IsBehind())
+
+	assert.Equal(t, "v2.0.0", results[1].Wanted)
+	assert.True(t, results[1].IsBehind())
+
+	assert.NotNil(t, results[2].Err)
+	assert.Equal(t, "v1.0.0", results[2].Current)
+}
+
+func TestCheckOutdatedInstalledWithoutLockfileEntry(t *testing.T) {
+	installDir, untarDir := t.TempDir(), t.TempDir()
+
+	for _, name := range []string{"org-assert.sh", "legacy-assert.sh"} {
+		metadata, err := NewPackageInstallerWith(
+			PackageInstallerWithName(name),
+			PackageInstallerWithVersion("v1.0.0"),
+			PackageInstallerWithFiles([]string{"assert.sh"}),
+		)
+		require.Nil(t, err)
+
+		asset := NewReleaseAssets(name, "v1.0.0", "testdata/sourceTarFile.tar.gz", filepath.Join(untarDir, name))
+		require.Nil(t, asset.Install(&metadata, installDir))
+	}
+
+	lock := NewLockfile()
+	lock.Put(LockedPackage{Organization: "org", Name: "assert.sh", Spec: "org/assert.sh:latest", Version: "v1.0.0", Provider: GithubProviderName})
+
+	finder := newMockReleaseVersionFinder()
+	finder.LatestFn = func(organization string, name string) (string, error) {
+		return "v1.1.0", nil
+	}
+
+	results, err := CheckOutdatedInstalled(installDir, lock, func(LockedPackage) (ReleaseVersionFinder, error) {
+		return finder, nil
+	}, 1)
+	require.Nil(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "org/assert.sh", results[0].Name())
+	assert.True(t, results[0].IsBehind())
+
+	assert.True(t, results[1].IsUnlocked())
+	assert.Equal(t, "legacy-assert.sh", results[1].Name())
+	assert.Equal(t, "v1.0.0", results[1].Current)
+	assert.Equal(t, UnknownVersion, results[1].Latest)
+	assert.False(t, results[1].IsBehind())
+}
+
+func TestOutdatedPackageIsBehind(t *testing.T) {
+	assert.True(t, OutdatedPackage{Current: "v1.0", Latest: "v1.1"}.IsBehind())
+	assert.False(t, OutdatedPackage{Current: "v1.1", Latest: "1.1.0"}.IsBehind())
+	assert.False(t, OutdatedPackage{Current: "v2.0", Latest: "v1.1"}.IsBehind())
+	assert.True(t, OutdatedPackage{Current: "master", Latest: "v1.1"}.IsBehind())
+	assert.False(t, OutdatedPackage{Current: "nightly", Latest: "nightly"}.IsBehind())
+}
@@ -12,12 +12,16 @@ import (
 )
 
 type PackageInstaller struct {
-	Manifest string   `json:"-"`
-	Name     string   `json:"name,omitempty"`
-	Version  string   `json:"version,omitempty"`
-	Scripts  []string `json:"scripts,omitempty"`
-	Files    []string `json:"files,omitempty"`
-	BinDir   string   `json:"-"`
+	Manifest     string            `json:"-"`
+	Name         string            `json:"name,omitempty"`
+	Version      string            `json:"version,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	Scripts      []string          `json:"scripts,omitempty"`
+	Files        []string          `json:"files,omitempty"`
+	Dependencies map[string]string `json:"dependencies,omitempty"`
+	BinDir       string            `json:"-"`
+	// Dir is the directory the manifest was read from
+	Dir string `json:"-"`
 }
 
 var (
@@ -77,6 +81,13 @@ func PackageInstallerWithFiles(files []string) func(*PackageInstaller) error {
 	}
 }
 
+func PackageInstallerWithDependencies(dependencies map[string]string) func(*PackageInstaller) error {
+	return func(p *PackageInstaller) error {
+		p.Dependencies = dependencies
+		return nil
+	}
+}
+
 func PackageInstallerWithBinDir(binDir string) func(*PackageInstaller) error {
 	return func(p *PackageInstaller) error {
 		p.BinDir = binDir
@@ -98,6 +109,13 @@ func PackageInstallerWithVersion(version string) func(*PackageInstaller) error {
 	}
 }
 
+func PackageInstallerWithDescription(description string) func(*PackageInstaller) error {
+	return func(p *PackageInstaller) error {
+		p.Description = description
+		return nil
+	}
+}
+
 func PackageInstallerWithName(name string) func(*PackageInstaller) error {
 	return func(p *PackageInstaller) error {
 		p.Name = name
@@ -148,8 +166,12 @@ func NewPackageInstallerFromFileName(filePath string) (*PackageInstaller, error)
 		PackageInstallerWithVersion(data.Version),
 		PackageInstallerWithScripts(data.Scripts),
 		PackageInstallerWithFiles(data.Files),
+		PackageInstallerWithDependencies(data.Dependencies),
+		PackageInstallerWithDescription(data.Description),
 	)
 
+	newPackageInstaller.Dir = filepath.Dir(filePath)
+
 	return &newPackageInstaller, err
 }
 
@@ -176,6 +198,20 @@ func (packageMetadata *PackageInstaller) LinkFiles() []string {
 	return f
 }
 
+func (packageMetadata *PackageInstaller) DependencyPackages() ([]FullyQualifyPackage, error) {
+	return dependencyPackages(packageMetadata.Dependencies)
+}
+
+func (packageMetadata *PackageInstaller) BinLinks(installPath string) []string {
+	f := make([]string, 0)
+
+	for _, file := range packageMetadata.LinkFiles() {
+		f = append(f, filepath.Join(installPath, packageMetadata.BinDir, filepath.Base(file)))
+	}
+
+	return f
+}
+
 func (packageMetadata *PackageInstaller) InstallationFilesCount() int {
 	return len(packageMetadata.Files) + len(packageMetadata.Scripts) + 1
 }
@@ -240,29 +276,65 @@ func (packageMetadata *PackageInstaller) Install(sourceDir string, destDir strin
 			return errors.New(fmt.Sprintf("Error Coping %s to %s", src, dst))
 		}
 
-		dstLink := filepath.Join(binDir, filepath.Base(srcFile))
-
-		symlinkPathTmp := dstLink + ".tmp"
-		if err := os.Remove(symlinkPathTmp); err != nil && !os.IsNotExist(err) {
-			return errors.New(fmt.Sprintf("Error Unlinking Symlink from %s", dst))
+		if err := packageMetadata.link(dst, binDir); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// Link creates the bin links of every script already installed at destDir
+func (packageMetadata *PackageInstaller) Link(destDir string) error {
+	binDir := filepath.Join(filepath.Dir(destDir), packageMetadata.BinDir)
+	if err := os.MkdirAll(binDir, 0755); nil != err {
+		return errors.New(fmt.Sprintf("Error Creating bin dir %s", binDir))
+	}
+
+	for _, srcFile := range packageMetadata.LinkFiles() {
+		dst := filepath.Join(destDir, srcFile)
 
-		//ToDo coverage test
-		if false == filepath.IsAbs(dst) {
-			if dst, err = filepath.Rel(binDir, dst); err != nil {
-				return errors.New(fmt.Sprintf("Error getting relative representation of path %s", dst))
-			}
+		if _, err := os.Stat(dst); errors.Is(err, os.ErrNotExist) {
+			return errors.New(fmt.Sprintf("Source File not found %s", dst))
 		}
 
-		if err := os.Symlink(dst, symlinkPathTmp); err != nil {
-			return errors.New(fmt.Sprintf("Error Creating Symlink from %s to %s", src, dst))
+		if err := packageMetadata.link(dst, binDir); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
 
-		if err := os.Rename(symlinkPathTmp, dstLink); err != nil {
-			return errors.New(fmt.Sprintf("Error Renaming Symlink from %s to %s", src, dst))
+func (packageMetadata *PackageInstaller) link(dst string, binDir string) error {
+	var err error
+
+	dstLink := filepath.Join(binDir, filepath.Base(dst))
+
+	symlinkPathTmp := dstLink + ".tmp"
+	if err := os.Remove(symlinkPathTmp); err != nil && !os.IsNotExist(err) {
+		return errors.New(fmt.Sprintf("Error Unlinking Symlink from %s", dst))
+	}
+
+	target := dst
+
+	//ToDo coverage test
+	if false == filepath.IsAbs(target) {
+		if target, err = filepath.Rel(binDir, target); err != nil {
+			return errors.New(fmt.Sprintf("Error getting relative representation of path %s", target))
 		}
 	}
 
+	if err := os.Symlink(target, symlinkPathTmp); err != nil {
+		return errors.New(fmt.Sprintf("Error Creating Symlink from %s to %s", dstLink, target))
+	}
+
+	if err := os.Rename(symlinkPathTmp, dstLink); err != nil {
+		_ = os.Remove(symlinkPathTmp)
+
+		return errors.New(fmt.Sprintf("Error Renaming Symlink from %s to %s", symlinkPathTmp, dstLink))
+	}
+
 	return nil
 }
 
@@ -351,3 +423,24 @@ func PackagesInstalled(releaseDir string) ([]*PackageInstaller, error) {
 
 	return assets, nil
 }
+
+// InstalledPackages filters PackagesInstalled down to the manifests Install writes, one per directory directly
+// under releaseDir and named after it, so any other json file shipped inside a package is skipped
+func InstalledPackages(releaseDir string) ([]*PackageInstaller, error) {
+	installed := make([]*PackageInstaller, 0)
+
+	packages, err := PackagesInstalled(releaseDir)
+	if err != nil {
+		return installed, err
+	}
+
+	for _, pkg := range packages {
+		if filepath.Clean(filepath.Dir(pkg.Dir)) != filepath.Clean(releaseDir) || pkg.Name != filepath.Base(pkg.Dir) {
+			continue
+		}
+
+		installed = append(installed, pkg)
+	}
+
+	return installed, nil
+}
@@ -15,6 +15,7 @@ func TestUnmarshall(t *testing.T) {
 	assert.Equal(t, packageFile.Manifest, "package.json")
 	assert.Equal(t, packageFile.Name, "test-package")
 	assert.Equal(t, packageFile.Version, "0.0.1")
+	assert.Equal(t, packageFile.Description, "test-description")
 
 	assert.Equal(t, packageFile.Scripts, []string{"src/scripts/file1", "src/scripts/file2"})
 	assert.Equal(t, packageFile.Files, []string{"src/files/file1", "src/files/file2"})
@@ -138,3 +139,42 @@ func TestPackagesInstalled(t *testing.T) {
 	_, err = PackagesInstalled("testdata/not_found")
 	assert.NotNil(t, err)
 }
+
+func TestInstalledPackages(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	installFolder, _ := os.MkdirTemp("", "temp-install-folder")
+	defer os.RemoveAll(installFolder)
+
+	asset := NewReleaseAssets("org-assert.sh", "v1.1", "testdata/sourceTarFile.tar.gz", tempFolder)
+
+	packageInstaller, err := NewPackageInstallerWith(
+		PackageInstallerWithName(asset.name),
+		PackageInstallerWithVersion(asset.version),
+		PackageInstallerWithFiles([]string{"assert.sh", "package.json"}),
+		PackageInstallerWithScripts([]string{"tests.sh"}),
+	)
+	require.Nil(t, err)
+
+	packageInstaller.Manifest = "manifest.json"
+	require.Nil(t, asset.Install(&packageInstaller, installFolder))
+
+	err = os.WriteFile(filepath.Join(installFolder, "stray.json"), []byte("{\"name\":\"stray\"}"), 0644)
+	require.Nil(t, err)
+
+	packages, err := PackagesInstalled(installFolder)
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(packages))
+
+	packages, err = InstalledPackages(installFolder)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(packages))
+
+	assert.Equal(t, "org-assert.sh", packages[0].Name)
+	assert.Equal(t, filepath.Join(installFolder, "org-assert.sh"), packages[0].Dir)
+	assert.Equal(t, []string{filepath.Join(installFolder, "bin", "tests.sh")}, packages[0].BinLinks(installFolder))
+
+	_, err = InstalledPackages("testdata/not_found")
+	assert.NotNil(t, err)
+}
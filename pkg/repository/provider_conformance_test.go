@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testProviders is a provider and finder built against the test server of a provider, hostname is the one it
+// describes itself with
+type testProviders struct {
+	provider ReleasesProvider
+	finder   ReleaseVersionFinder
+	hostname string
+}
+
+// testProviderFixture is a provider whose test server publishes v1.1 of organization/assert.sh as
+// testdata/sourceTarFile.tar.gz, among versions in the order they are listed, latest is the newest stable one and
+// matching the one ~1.1 resolves to
+type testProviderFixture struct {
+	name         string
+	organization string
+	versions     []string
+	latest       string
+	matching     string
+	missing      error
+	newProviders func(t *testing.T) testProviders
+}
+
+func testProviderFixtures() []testProviderFixture {
+	giteaVersions := []string{"v2.0-rc.1"}
+	for minor := giteaReleasesPageSize - 3; minor >= 0; minor-- {
+		giteaVersions = append(giteaVersions, fmt.Sprintf("v1.%d", minor))
+	}
+
+	return []testProviderFixture{
+		{
+			name:         GithubProviderName,
+			organization: "rafaelcalleja",
+			versions:     []string{"v1.2.0", "v1.1.0"},
+			latest:       "v1.2.0",
+			matching:     "v1.1.0",
+			missing:      ErrNoMatchingReleaseVersion,
+			newProviders: func(t *testing.T) testProviders {
+				server := newTestGithubServer(t, "secret")
+				t.Cleanup(server.Close)
+
+				client := newTestGithubClient(t, server, "secret")
+
+				provider, err := NewGithubProviderWith(WithGithubClient(client))
+				require.Nil(t, err)
+
+				finder, err := NewGithubVersionFinderWith(FinderWithClient(client))
+				require.Nil(t, err)
+
+				return testProviders{provider: provider, finder: finder, hostname: GithubRepository}
+			},
+		},
+		{
+			name:         GitlabProviderName,
+			organization: "group/sub",
+			versions:     []string{"v1.1", "v1.0"},
+			latest:       "v1.1",
+			matching:     "v1.1",
+			missing:      ErrHttpStatus,
+			newProviders: func(t *testing.T) testProviders {
+				server := newTestGitlabServer(t, "secret")
+				t.Cleanup(server.Close)
+
+				provider, err := NewGitlabProviderWith(
+					GitlabWithHostname("gitlab.corp.example"),
+					GitlabWithBaseURL(server.URL+"/api/v4/"),
+					GitlabWithToken("secret"),
+					GitlabWithClient(server.Client()),
+				)
+				require.Nil(t, err)
+
+				return testProviders{provider: provider, finder: provider, hostname: "gitlab.corp.example"}
+			},
+		},
+		{
+			name:         GiteaProviderName,
+			organization: "rafaelcalleja",
+			versions:     append(giteaVersions, "v0.1"),
+			latest:       fmt.Sprintf("v1.%d", giteaReleasesPageSize-3),
+			matching:     "v1.1",
+			missing:      ErrHttpStatus,
+			newProviders: func(t *testing.T) testProviders {
+				server := newTestGiteaServer(t, "secret")
+				t.Cleanup(server.Close)
+
+				provider, err := NewGiteaProviderWith(
+					GiteaWithBaseURL(server.URL+"/api/v1/"),
+					GiteaWithToken("secret"),
+					GiteaWithClient(server.Client()),
+				)
+				require.Nil(t, err)
+
+				return testProviders{provider: provider, finder: provider, hostname: server.Listener.Addr().String()}
+			},
+		},
+		{
+			name:         HttpProviderName,
+			organization: "rafaelcalleja",
+			versions:     []string{"v1.0", "v1.1", "v2.0-rc.1"},
+			latest:       "v1.1",
+			matching:     "v1.1",
+			missing:      ErrHttpStatus,
+			newProviders: func(t *testing.T) testProviders {
+				server := newTestHttpServer(t)
+				t.Cleanup(server.Close)
+
+				provider, err := NewHttpProviderWith(
+					HttpProviderWithURLTemplate(server.URL+"/{{.Organization}}/{{.Name}}/{{.Version}}.tar.gz"),
+					HttpProviderWithIndexURL(server.URL+"/{{.Organization}}/{{.Name}}/index.json"),
+					HttpProviderWithClient(server.Client()),
+				)
+				require.Nil(t, err)
+
+				return testProviders{provider: provider, finder: provider, hostname: server.Listener.Addr().String()}
+			},
+		},
+		{
+			name:         RegistryProviderName,
+			organization: "rafaelcalleja",
+			versions:     []string{"v2.0-rc.1", "v1.2", "v1.1", "v1.0"},
+			latest:       "v1.2",
+			matching:     "v1.1",
+			missing:      ErrNoMatchingReleaseVersion,
+			newProviders: func(t *testing.T) testProviders {
+				server, mirrorDir := newTestRegistry(t)
+				t.Cleanup(func() {
+					server.Close()
+					_ = os.RemoveAll(mirrorDir)
+				})
+
+				provider, finder, err := NewProviders(RegistryProviderName, ProviderOptions{Remote: server.URL + "/"})
+				require.Nil(t, err)
+
+				return testProviders{provider: provider, finder: finder, hostname: server.Listener.Addr().String()}
+			},
+		},
+		{
+			name:         MirrorProviderName,
+			organization: "rafaelcalleja",
+			versions:     []string{"v2.0-rc.1", "v1.2", "v1.1", "v1.0"},
+			latest:       "v1.2",
+			matching:     "v1.1",
+			missing:      ErrNoMatchingReleaseVersion,
+			newProviders: func(t *testing.T) testProviders {
+				mirrorDir := t.TempDir()
+				newTestMirror(t, mirrorDir)
+
+				provider, finder, err := NewProviders(MirrorProviderName, ProviderOptions{Remote: mirrorDir})
+				require.Nil(t, err)
+
+				return testProviders{provider: provider, finder: finder}
+			},
+		},
+	}
+}
+
+func TestProviderConformance(t *testing.T) {
+	for _, fixture := range testProviderFixtures() {
+		t.Run(fixture.name, func(t *testing.T) {
+			providers := fixture.newProviders(t)
+
+			name, hostname := DescribeProvider(providers.provider)
+			assert.Equal(t, fixture.name, name)
+			assert.Equal(t, providers.hostname, hostname)
+
+			versions, err := providers.finder.List(fixture.organization, "assert.sh")
+			require.Nil(t, err)
+			assert.Equal(t, fixture.versions, versions)
+
+			latest, err := providers.finder.Latest(fixture.organization, "assert.sh")
+			require.Nil(t, err)
+			assert.Equal(t, fixture.latest, latest)
+
+			matching, err := NewReleaseMatchingVersion(fixture.organization, "assert.sh", "~1.1", providers.finder)
+			require.Nil(t, err)
+			assert.Equal(t, fixture.matching, matching.Version())
+
+			_, err = providers.finder.Latest(fixture.organization, "missing")
+			assert.True(t, errors.Is(err, fixture.missing), err)
+
+			installDir := t.TempDir()
+
+			releaseVersion := NewReleaseVersion(fixture.organization, "assert.sh", "v1.1")
+			require.Nil(t, releaseVersion.DownloadAndInstallAsset(providers.provider, installDir))
+			assert.True(t, releaseVersion.IsVersionInstalled("v1.1", installDir))
+			assert.DirExists(t, filepath.Join(installDir, PackageInstallName(fixture.organization, "assert.sh")))
+
+			downloadDir := t.TempDir()
+
+			if signatures, ok := providers.provider.(SignaturesProvider); ok {
+				err = signatures.DownloadSignature(&releaseVersion, "missing.tar.gz", downloadDir)
+				assert.True(t, errors.Is(err, ErrSignatureNotFound), err)
+			}
+
+			if checksums, ok := providers.provider.(ChecksumsProvider); ok {
+				err = checksums.DownloadChecksums(&releaseVersion, downloadDir)
+				assert.True(t, errors.Is(err, ErrChecksumsNotPublished), err)
+			}
+		})
+	}
+}
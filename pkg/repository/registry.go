@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var ErrUnknownProvider = errors.New("unknown releases provider")
+
+// ProviderOptions holds everything a registered provider may be built from, each provider reads the fields it uses
+type ProviderOptions struct {
+	// Hostname of self-managed instances, empty for the provider default
+	Hostname string
+	// URL of url specs such as git+ssh://host/team/name.git
+	URL string
+	// Remote is a git remote or an http url template, rendered with the organization and name of the package, the dir
+	// of a mirror or the base url of a registry
+	Remote   string
+	IndexURL string
+	Archive  string
+	Asset    string
+	Factory  *cmdutil.Factory
+}
+
+// ProviderConstructor builds the provider and version finder pair of a scheme
+type ProviderConstructor func(options ProviderOptions) (ReleasesProvider, ReleaseVersionFinder, error)
+
+var providerRegistry = struct {
+	sync.RWMutex
+	constructors map[string]ProviderConstructor
+}{constructors: make(map[string]ProviderConstructor)}
+
+// RegisterProvider makes a provider available to specs such as <scheme>:org/name:v1, providers call it from init
+// and registering a scheme twice panics
+func RegisterProvider(scheme string, constructor ProviderConstructor) {
+	providerRegistry.Lock()
+	defer providerRegistry.Unlock()
+
+	if nil == constructor {
+		panic(fmt.Sprintf("repository: provider %s constructor is nil", scheme))
+	}
+
+	if _, duplicated := providerRegistry.constructors[scheme]; duplicated {
+		panic(fmt.Sprintf("repository: provider %s registered twice", scheme))
+	}
+
+	providerRegistry.constructors[scheme] = constructor
+}
+
+// RegisteredProviders returns the registered schemes sorted by name
+func RegisteredProviders() []string {
+	providerRegistry.RLock()
+	defer providerRegistry.RUnlock()
+
+	schemes := make([]string, 0, len(providerRegistry.constructors))
+	for scheme := range providerRegistry.constructors {
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+
+	return schemes
+}
+
+// NewProviders builds the provider and version finder pair registered for scheme
+func NewProviders(scheme string, options ProviderOptions) (ReleasesProvider, ReleaseVersionFinder, error) {
+	providerRegistry.RLock()
+	constructor, ok := providerRegistry.constructors[scheme]
+	providerRegistry.RUnlock()
+
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s, use %s", ErrUnknownProvider, scheme, strings.Join(RegisteredProviders(), ", "))
+	}
+
+	return constructor(options)
+}
+
+// archive is the requested source archive format, tar.gz when empty
+func (options ProviderOptions) archive() string {
+	if "" == options.Archive {
+		return ArchiveFormatTarGz
+	}
+
+	return options.Archive
+}
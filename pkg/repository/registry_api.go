@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// The registry API is read-only JSON over http, served by the serve command from a mirror dir:
+//
+//	GET /v1/packages                                    RegistryPackageList
+//	GET /v1/packages/<org>/<name>                       RegistryPackage
+//	GET /v1/packages/<org>/<name>/<version>/archive     the release file
+//	GET /v1/packages/<org>/<name>/<version>/signature   the detached signature of the release file
+//
+// Every path segment is url path escaped, nested organizations such as group/sub are sent as group%2Fsub. Errors
+// answer a RegistryError with the http status
+const (
+	RegistryAPIVersion = "v1"
+	RegistryMediaType  = "application/json"
+)
+
+type RegistryPackageList struct {
+	APIVersion string   `json:"apiVersion"`
+	Packages   []string `json:"packages"`
+}
+
+// RegistryPackage Latest is the highest stable version, otherwise the newest release
+type RegistryPackage struct {
+	APIVersion   string            `json:"apiVersion"`
+	Organization string            `json:"organization"`
+	Name         string            `json:"name"`
+	Latest       string            `json:"latest"`
+	Releases     []RegistryRelease `json:"releases"`
+}
+
+// RegistryRelease ArchiveURL and SignatureURL are relative to the registry base url, SignatureURL is empty for
+// releases published without signature
+type RegistryRelease struct {
+	Version      string `json:"version"`
+	File         string `json:"file"`
+	Sha256       string `json:"sha256"`
+	ArchiveURL   string `json:"archiveUrl"`
+	SignatureURL string `json:"signatureUrl,omitempty"`
+}
+
+type RegistryError struct {
+	APIVersion string `json:"apiVersion"`
+	Error      string `json:"error"`
+}
+
+func RegistryPackageListPath() string {
+	return fmt.Sprintf("/%s/packages", RegistryAPIVersion)
+}
+
+func RegistryPackagePath(organization string, name string) string {
+	return fmt.Sprintf("%s/%s/%s", RegistryPackageListPath(), url.PathEscape(organization), url.PathEscape(name))
+}
+
+func RegistryArchivePath(organization string, name string, version string) string {
+	return fmt.Sprintf("%s/%s/archive", RegistryPackagePath(organization, name), url.PathEscape(version))
+}
+
+func RegistrySignaturePath(organization string, name string, version string) string {
+	return fmt.Sprintf("%s/%s/signature", RegistryPackagePath(organization, name), url.PathEscape(version))
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const RegistryProviderName = "registry"
+
+var (
+	ErrRegistryURLNotSet        = errors.New("registry provider requires a base url or a hostname")
+	ErrRegistryChecksumMismatch = errors.New("registry file doesn't match its sha256")
+)
+
+func init() {
+	RegisterProvider(RegistryProviderName, newRegistryProviders)
+}
+
+// RegistryProvider downloads releases from a registry started with the serve command
+type RegistryProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewRegistryProviderWith(options ...func(*RegistryProvider) error) (*RegistryProvider, error) {
+	var registryProvider = new(RegistryProvider)
+
+	for _, option := range options {
+		err := option(registryProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if "" == registryProvider.baseURL {
+		return nil, ErrRegistryURLNotSet
+	}
+
+	registryProvider.baseURL = strings.TrimSuffix(registryProvider.baseURL, "/")
+
+	if nil == registryProvider.client {
+		registryProvider.client = &http.Client{Timeout: DefaultHttpTimeout}
+	}
+
+	return registryProvider, nil
+}
+
+func RegistryWithBaseURL(baseURL string) func(*RegistryProvider) error {
+	return func(r *RegistryProvider) error {
+		r.baseURL = baseURL
+		return nil
+	}
+}
+
+func RegistryWithClient(client *http.Client) func(*RegistryProvider) error {
+	return func(r *RegistryProvider) error {
+		r.client = client
+		return nil
+	}
+}
+
+func NewRegistryProvider(baseURL string) (*RegistryProvider, error) {
+	return NewRegistryProviderWith(RegistryWithBaseURL(baseURL))
+}
+
+// newRegistryProviders uses --remote as base url, registry://host/org/name:v1 specs use https://host
+func newRegistryProviders(options ProviderOptions) (ReleasesProvider, ReleaseVersionFinder, error) {
+	baseURL := options.Remote
+	if "" == baseURL && "" != options.Hostname {
+		baseURL = fmt.Sprintf("https://%s", options.Hostname)
+	}
+
+	provider, err := NewRegistryProvider(baseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return provider, provider, nil
+}
+
+func (r *RegistryProvider) ProviderName() string {
+	return RegistryProviderName
+}
+
+func (r *RegistryProvider) Hostname() string {
+	parsed, err := url.Parse(r.baseURL)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Host
+}
+
+// ServesDependencies is true, registries are served to hosts that can't reach the providers of the dependencies
+func (r *RegistryProvider) ServesDependencies() bool {
+	return true
+}
+
+func (r *RegistryProvider) DownloadVariant() string {
+	return r.baseURL
+}
+
+func (r *RegistryProvider) registryPackage(organization string, name string) (RegistryPackage, error) {
+	var registryPackage RegistryPackage
+
+	packageURL := r.baseURL + RegistryPackagePath(organization, name)
+
+	response, err := httpGet(r.client, packageURL, http.Header{"Accept": []string{RegistryMediaType}})
+	if err != nil {
+		return registryPackage, err
+	}
+
+	defer response.Body.Close()
+
+	if err = json.NewDecoder(response.Body).Decode(&registryPackage); err != nil {
+		return registryPackage, errors.New(fmt.Sprintf("Error unmarsalling %s", packageURL))
+	}
+
+	return registryPackage, nil
+}
+
+func (r *RegistryProvider) release(releaseVersion *ReleaseVersion) (RegistryRelease, error) {
+	registryPackage, err := r.registryPackage(releaseVersion.Organization, releaseVersion.Name)
+	if err != nil {
+		return RegistryRelease{}, err
+	}
+
+	for _, release := range registryPackage.Releases {
+		if release.Version == releaseVersion.Version() {
+			return release, nil
+		}
+	}
+
+	return RegistryRelease{}, fmt.Errorf("%w: %s:%s", ErrReleaseAssetNotFound,
+		LockKey(releaseVersion.Organization, releaseVersion.Name), releaseVersion.Version())
+}
+
+// Download fetches the release file and checks it against the sha256 published by the registry
+func (r *RegistryProvider) Download(releaseVersion *ReleaseVersion, downloadDir string) error {
+	release, err := r.release(releaseVersion)
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(downloadDir, filepath.Base(release.File))
+	if err = httpDownload(r.client, r.baseURL+release.ArchiveURL, http.Header{}, filePath); err != nil {
+		return err
+	}
+
+	sha256, err := FileSha256(filePath)
+	if err != nil {
+		return err
+	}
+
+	if sha256 != release.Sha256 {
+		return fmt.Errorf("%w: %s", ErrRegistryChecksumMismatch, r.baseURL+release.ArchiveURL)
+	}
+
+	return nil
+}
+
+func (r *RegistryProvider) DownloadSignature(releaseVersion *ReleaseVersion, assetName string, downloadDir string) error {
+	release, err := r.release(releaseVersion)
+	if err != nil {
+		return err
+	}
+
+	if "" == release.SignatureURL || assetName != filepath.Base(release.File) {
+		return fmt.Errorf("%w: %s%s", ErrSignatureNotFound, assetName, SignatureExtension)
+	}
+
+	return httpDownload(r.client, r.baseURL+release.SignatureURL, http.Header{},
+		filepath.Join(downloadDir, assetName+SignatureExtension))
+}
+
+func (r *RegistryProvider) Latest(organization string, name string) (string, error) {
+	registryPackage, err := r.registryPackage(organization, name)
+
+	var statusErr *HttpStatusError
+	if (errors.As(err, &statusErr) && http.StatusNotFound == statusErr.StatusCode) ||
+		(nil == err && "" == registryPackage.Latest) {
+		return "", fmt.Errorf("%w: %s/%s", ErrNoMatchingReleaseVersion, organization, name)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return registryPackage.Latest, nil
+}
+
+func (r *RegistryProvider) List(organization string, name string) ([]string, error) {
+	registryPackage, err := r.registryPackage(organization, name)
+	if err != nil {
+		return []string{}, err
+	}
+
+	versions := make([]string, 0, len(registryPackage.Releases))
+	for _, release := range registryPackage.Releases {
+		versions = append(versions, release.Version)
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RegistryServer serves the mirror dir at root with the registry API, the index is read on every request so the
+// mirror command can update a running registry
+type RegistryServer struct {
+	root string
+}
+
+func NewRegistryServer(root string) (*RegistryServer, error) {
+	if _, err := NewMirrorProvider(root); err != nil {
+		return nil, err
+	}
+
+	return &RegistryServer{root: root}, nil
+}
+
+func (s *RegistryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if http.MethodGet != r.Method && http.MethodHead != r.Method {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeRegistryError(w, http.StatusMethodNotAllowed, "the registry is read-only")
+
+		return
+	}
+
+	segments, err := registryPathSegments(r.URL.EscapedPath())
+	if err != nil || len(segments) < 2 || RegistryAPIVersion != segments[0] || "packages" != segments[1] {
+		writeRegistryError(w, http.StatusNotFound, fmt.Sprintf("unknown path %s", r.URL.Path))
+
+		return
+	}
+
+	mirror, err := NewMirrorProvider(s.root)
+	if err != nil {
+		writeRegistryError(w, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	switch len(segments) {
+	case 2:
+		s.servePackageList(w, mirror)
+	case 4:
+		s.servePackage(w, mirror, segments[2], segments[3])
+	case 6:
+		s.serveFile(w, r, mirror, segments[2], segments[3], segments[4], segments[5])
+	default:
+		writeRegistryError(w, http.StatusNotFound, fmt.Sprintf("unknown path %s", r.URL.Path))
+	}
+}
+
+func (s *RegistryServer) servePackageList(w http.ResponseWriter, mirror *MirrorProvider) {
+	packages := make([]string, 0, len(mirror.index.Packages))
+	for key := range mirror.index.Packages {
+		packages = append(packages, key)
+	}
+
+	sort.Strings(packages)
+
+	writeRegistryJSON(w, RegistryPackageList{APIVersion: RegistryAPIVersion, Packages: packages})
+}
+
+func (s *RegistryServer) servePackage(w http.ResponseWriter, mirror *MirrorProvider, organization string, name string) {
+	latest, err := mirror.Latest(organization, name)
+	if err != nil {
+		writeRegistryError(w, http.StatusNotFound, fmt.Sprintf("package %s not found", LockKey(organization, name)))
+
+		return
+	}
+
+	registryPackage := RegistryPackage{
+		APIVersion:   RegistryAPIVersion,
+		Organization: organization,
+		Name:         name,
+		Latest:       latest,
+		Releases:     make([]RegistryRelease, 0),
+	}
+
+	for _, release := range mirror.index.Releases(organization, name) {
+		registryRelease := RegistryRelease{
+			Version:    release.Version,
+			File:       path.Base(release.File),
+			Sha256:     release.Sha256,
+			ArchiveURL: RegistryArchivePath(organization, name, release.Version),
+		}
+
+		if "" != release.Signature {
+			registryRelease.SignatureURL = RegistrySignaturePath(organization, name, release.Version)
+		}
+
+		registryPackage.Releases = append(registryPackage.Releases, registryRelease)
+	}
+
+	writeRegistryJSON(w, registryPackage)
+}
+
+func (s *RegistryServer) serveFile(
+	w http.ResponseWriter,
+	r *http.Request,
+	mirror *MirrorProvider,
+	organization string,
+	name string,
+	version string,
+	kind string,
+) {
+	release, ok := mirror.index.Release(organization, name, version)
+	if false == ok {
+		writeRegistryError(w, http.StatusNotFound, fmt.Sprintf("release %s:%s not found", LockKey(organization, name), version))
+
+		return
+	}
+
+	file := ""
+	switch kind {
+	case "archive":
+		file = release.File
+	case "signature":
+		file = release.Signature
+	default:
+		writeRegistryError(w, http.StatusNotFound, fmt.Sprintf("unknown path %s", r.URL.Path))
+
+		return
+	}
+
+	if "" == file {
+		writeRegistryError(w, http.StatusNotFound, fmt.Sprintf("release %s:%s has no %s", LockKey(organization, name), version, kind))
+
+		return
+	}
+
+	// the index is written by the mirror command, cleaning keeps a tampered one inside root anyway
+	filePath := filepath.Join(s.root, filepath.FromSlash(path.Clean("/"+file)))
+
+	content, err := os.Open(filePath)
+	if err != nil {
+		writeRegistryError(w, http.StatusNotFound, fmt.Sprintf("release %s:%s %s is missing", LockKey(organization, name), version, kind))
+
+		return
+	}
+
+	defer content.Close()
+
+	stat, err := content.Stat()
+	if err != nil {
+		writeRegistryError(w, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(file)))
+	http.ServeContent(w, r, path.Base(file), stat.ModTime(), content)
+}
+
+// registryPathSegments splits the escaped path before unescaping, escaped slashes stay inside their segment
+func registryPathSegments(escapedPath string) ([]string, error) {
+	segments := strings.Split(strings.Trim(escapedPath, "/"), "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, err
+		}
+
+		if "" == unescaped {
+			return nil, errors.New(fmt.Sprintf("empty segment in %s", escapedPath))
+		}
+
+		segments[i] = unescaped
+	}
+
+	return segments, nil
+}
+
+func writeRegistryJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", RegistryMediaType)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func writeRegistryError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", RegistryMediaType)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(RegistryError{APIVersion: RegistryAPIVersion, Error: message})
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestRegistry(t *testing.T) (*httptest.Server, string) {
+	mirrorDir, _ := os.MkdirTemp("", "temp-test-mirror-folder")
+	newTestMirror(t, mirrorDir)
+
+	registry, err := NewRegistryServer(mirrorDir)
+	require.Nil(t, err)
+
+	return httptest.NewServer(registry), mirrorDir
+}
+
+func TestRegistryServer(t *testing.T) {
+	server, mirrorDir := newTestRegistry(t)
+	defer os.RemoveAll(mirrorDir)
+	defer server.Close()
+
+	response, err := http.Get(server.URL + RegistryPackageListPath())
+	require.Nil(t, err)
+	defer response.Body.Close()
+
+	var packageList RegistryPackageList
+	require.Nil(t, json.NewDecoder(response.Body).Decode(&packageList))
+	assert.Equal(t, RegistryAPIVersion, packageList.APIVersion)
+	assert.Equal(t, []string{"group/sub/signed", "rafaelcalleja/assert.sh"}, packageList.Packages)
+
+	response, err = http.Get(server.URL + RegistryPackagePath("group/sub", "signed"))
+	require.Nil(t, err)
+	defer response.Body.Close()
+
+	var registryPackage RegistryPackage
+	require.Nil(t, json.NewDecoder(response.Body).Decode(&registryPackage))
+	assert.Equal(t, "group/sub", registryPackage.Organization)
+	assert.Equal(t, "v3.0", registryPackage.Latest)
+	require.Len(t, registryPackage.Releases, 1)
+	assert.Equal(t, "signed.tar.gz", registryPackage.Releases[0].File)
+	assert.Equal(t, "/v1/packages/group%2Fsub/signed/v3.0/signature", registryPackage.Releases[0].SignatureURL)
+
+	for path, statusCode := range map[string]int{
+		"/v2/packages": http.StatusNotFound,
+		RegistryPackagePath("rafaelcalleja", "missing"):               http.StatusNotFound,
+		RegistryArchivePath("rafaelcalleja", "assert.sh", "v9.9"):     http.StatusNotFound,
+		RegistrySignaturePath("rafaelcalleja", "assert.sh", "v1.1"):   http.StatusNotFound,
+		RegistryPackagePath("rafaelcalleja", "assert.sh") + "/v1.1/x": http.StatusNotFound,
+		RegistryArchivePath("rafaelcalleja", "assert.sh", "v1.1"):     http.StatusOK,
+	} {
+		response, err = http.Get(server.URL + path)
+		require.Nil(t, err)
+		_ = response.Body.Close()
+		assert.Equal(t, statusCode, response.StatusCode, path)
+	}
+
+	response, err = http.Post(server.URL+RegistryPackageListPath(), RegistryMediaType, nil)
+	require.Nil(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+
+	_, err = NewRegistryServer(filepath.Join(mirrorDir, "missing"))
+	assert.True(t, errors.Is(err, ErrMirrorIndexNotFound))
+}
+
+func TestRegistryProvider(t *testing.T) {
+	server, mirrorDir := newTestRegistry(t)
+	defer os.RemoveAll(mirrorDir)
+	defer server.Close()
+
+	provider, _, err := NewProviders(RegistryProviderName, ProviderOptions{Remote: server.URL + "/"})
+	require.Nil(t, err)
+
+	assert.True(t, ServesDependencies(provider))
+	assert.False(t, isUncacheable(provider))
+
+	downloadDir, _ := os.MkdirTemp("", "temp-test-download-folder")
+	defer os.RemoveAll(downloadDir)
+
+	signed := NewReleaseVersion("group/sub", "signed", "v3.0")
+	signatures := provider.(SignaturesProvider)
+	require.Nil(t, signatures.DownloadSignature(&signed, "signed.tar.gz", downloadDir))
+	assert.FileExists(t, filepath.Join(downloadDir, "signed.tar.gz.sig"))
+
+	index, err := NewMirrorIndexFromDir(mirrorDir)
+	require.Nil(t, err)
+
+	release, _ := index.Release("rafaelcalleja", "assert.sh", "v1.0")
+	require.Nil(t, os.WriteFile(filepath.Join(mirrorDir, filepath.FromSlash(release.File)), []byte("tampered"), 0644))
+
+	tampered := NewReleaseVersion("rafaelcalleja", "assert.sh", "v1.0")
+	err = provider.Download(&tampered, downloadDir)
+	assert.True(t, errors.Is(err, ErrRegistryChecksumMismatch))
+
+	spec, err := NewFullyQualifyPackage("registry://registry.corp.example/org/name:v1")
+	require.Nil(t, err)
+
+	byHostname, _, err := NewProviders(spec.Scheme(), ProviderOptions{Hostname: spec.Hostname()})
+	require.Nil(t, err)
+	assert.Equal(t, "https://registry.corp.example", byHostname.(*RegistryProvider).DownloadVariant())
+
+	_, _, err = NewProviders(RegistryProviderName, ProviderOptions{})
+	assert.True(t, errors.Is(err, ErrRegistryURLNotSet))
+}
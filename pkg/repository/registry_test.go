@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	ghfactory "github.com/cli/cli/v2/pkg/cmd/factory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRegisteredProviders(t *testing.T) {
+	schemes := RegisteredProviders()
+
+	for _, scheme := range []string{GithubProviderName, GitlabProviderName, GiteaProviderName, ForgejoProviderName,
+		GitProviderName, "git+ssh", "git+file", HttpProviderName, MirrorProviderName, RegistryProviderName} {
+		assert.Contains(t, schemes, scheme)
+	}
+
+	_, _, err := NewProviders("svn", ProviderOptions{})
+	assert.True(t, errors.Is(err, ErrUnknownProvider))
+
+	assert.Panics(t, func() {
+		RegisterProvider(GithubProviderName, newGithubProviders)
+	})
+}
+
+func TestRegisterProvider(t *testing.T) {
+	finder := newMockReleaseVersionFinder()
+	provider := newMockReleasesProvider()
+
+	var received ProviderOptions
+	RegisterProvider("test-registry", func(options ProviderOptions) (ReleasesProvider, ReleaseVersionFinder, error) {
+		received = options
+
+		return provider, finder, nil
+	})
+
+	fqpVO, err := NewFullyQualifyPackage("test-registry://host.example/org/name:v1")
+	require.Nil(t, err)
+
+	builtProvider, builtFinder, err := NewProviders(fqpVO.Scheme(), ProviderOptions{Hostname: fqpVO.Hostname()})
+	require.Nil(t, err)
+
+	assert.Same(t, provider, builtProvider)
+	assert.Same(t, finder, builtFinder)
+	assert.Equal(t, "host.example", received.Hostname)
+}
+
+func TestNewProvidersFromSpec(t *testing.T) {
+	factory := ghfactory.New("0.0.0")
+
+	specs := map[string][2]string{
+		"git+ssh://git@git.corp.example/team/tool.git:v1": {GitProviderName, "git.corp.example"},
+		"gitlab://gitlab.corp.example/group/sub/name:v1":  {GitlabProviderName, "gitlab.corp.example"},
+		"forgejo://code.corp.example/org/name:v1":         {GiteaProviderName, "code.corp.example"},
+		"github:ghe.corp.example/org/name:v1":             {GithubProviderName, "ghe.corp.example"},
+		"org/name:v1":                                     {GithubProviderName, GithubRepository},
+	}
+
+	for spec, expected := range specs {
+		fqpVO, err := NewFullyQualifyPackage(spec)
+		require.Nil(t, err)
+
+		scheme := fqpVO.Scheme()
+		if "" == scheme {
+			scheme = GithubProviderName
+		}
+
+		provider, _, err := NewProviders(scheme, ProviderOptions{Hostname: fqpVO.Hostname(), URL: fqpVO.URL(), Factory: factory})
+		require.Nil(t, err, spec)
+
+		name, hostname := DescribeProvider(provider)
+		assert.Equal(t, expected[0], name, spec)
+		assert.Equal(t, expected[1], hostname, spec)
+	}
+
+	_, _, err := NewProviders(HttpProviderName, ProviderOptions{})
+	assert.True(t, errors.Is(err, ErrHttpURLTemplateNotSet))
+
+	_, _, err = NewProviders(GiteaProviderName, ProviderOptions{})
+	assert.True(t, errors.Is(err, ErrGiteaHostnameNotSet))
+
+	_, _, err = NewProviders(GithubProviderName, ProviderOptions{})
+	assert.True(t, errors.Is(err, ErrGithubFactoryNotSet))
+}
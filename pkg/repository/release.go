@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type ReleasesProvider interface {
@@ -14,6 +15,76 @@ type ReleasesProvider interface {
 
 type ReleaseVersionFinder interface {
 	Latest(organization string, name string) (string, error)
+	List(organization string, name string) ([]string, error)
+}
+
+// ProviderDescriber is implemented by providers that can be recorded in a lockfile
+type ProviderDescriber interface {
+	ProviderName() string
+	Hostname() string
+}
+
+// DownloadVariant is implemented by providers that may download different files for the same version, such as the
+// source archive or an uploaded asset, cached downloads are only reused for the same variant
+type DownloadVariant interface {
+	DownloadVariant() string
+}
+
+// AssetPatternProvider is implemented by providers that download a prebuilt release asset matching AssetPattern
+// instead of the source archive when it isn't empty
+type AssetPatternProvider interface {
+	AssetPattern() string
+}
+
+// DependencyServer is implemented by providers that serve the dependencies of their packages too, such as mirrors
+// of offline hosts which can't reach the providers those dependencies come from
+type DependencyServer interface {
+	ServesDependencies() bool
+}
+
+// UncacheableProvider is implemented by providers whose downloads are already local, they skip the download cache
+type UncacheableProvider interface {
+	Uncacheable() bool
+}
+
+// ServesDependencies reports whether the dependencies of the packages of provider have to be fetched from it
+func ServesDependencies(provider ReleasesProvider) bool {
+	server, ok := provider.(DependencyServer)
+
+	return ok && server.ServesDependencies()
+}
+
+func isUncacheable(provider ReleasesProvider) bool {
+	uncacheable, ok := provider.(UncacheableProvider)
+
+	return ok && uncacheable.Uncacheable()
+}
+
+// ReleaseAssetPatternProvider is implemented by providers whose asset pattern depends on the release, such as mirrors
+// holding releases downloaded with different patterns
+type ReleaseAssetPatternProvider interface {
+	ReleaseAssetPattern(releaseVersion *ReleaseVersion) string
+}
+
+// releaseAssetPattern is the asset pattern releaseVersion is downloaded with, "" for source archives
+func releaseAssetPattern(provider ReleasesProvider, releaseVersion *ReleaseVersion) string {
+	if patterned, ok := provider.(ReleaseAssetPatternProvider); ok {
+		return patterned.ReleaseAssetPattern(releaseVersion)
+	}
+
+	if patterned, ok := provider.(AssetPatternProvider); ok {
+		return patterned.AssetPattern()
+	}
+
+	return ""
+}
+
+func DescribeProvider(provider ReleasesProvider) (string, string) {
+	if describer, ok := provider.(ProviderDescriber); ok {
+		return describer.ProviderName(), describer.Hostname()
+	}
+
+	return "", ""
 }
 
 type ReleaseVersion struct {
@@ -129,6 +200,23 @@ func (releaseVersion *ReleaseVersion) HasPackageMetadata(releaseDir string) bool
 }
 
 func (releaseVersion *ReleaseVersion) DownloadAsset(provider ReleasesProvider, releaseDir string) (ReleaseAssets, error) {
+	return releaseVersion.DownloadCachedAsset(provider, releaseDir, nil)
+}
+
+// DownloadCachedAsset reuses the file cached for the provider hostname and version, a miss downloads it and stores it
+// in cache. Local and mirror providers and a nil cache always download
+func (releaseVersion *ReleaseVersion) DownloadCachedAsset(provider ReleasesProvider, releaseDir string, cache *DownloadCache) (ReleaseAssets, error) {
+	return releaseVersion.DownloadVerifiedAsset(provider, releaseDir, cache, nil)
+}
+
+// DownloadVerifiedAsset is DownloadCachedAsset running verify on the downloaded file before it is cached or
+// extracted, so a file failing its checksum is never decompressed
+func (releaseVersion *ReleaseVersion) DownloadVerifiedAsset(
+	provider ReleasesProvider,
+	releaseDir string,
+	cache *DownloadCache,
+	verify func(sourceFile string) error,
+) (ReleaseAssets, error) {
 	var err error
 
 	tempDirectory, err := os.MkdirTemp("", "temp-plugin-folder")
@@ -136,29 +224,118 @@ func (releaseVersion *ReleaseVersion) DownloadAsset(provider ReleasesProvider, r
 		return ReleaseAssets{}, errors.New(fmt.Sprintf("Error Downloading Plugin can't create temp dir %s", tempDirectory))
 	}
 
-	err = provider.Download(releaseVersion, tempDirectory)
+	providerName, hostname := DescribeProvider(provider)
+	if "" == hostname {
+		hostname = providerName
+	}
+
+	variant := ""
+	if describer, ok := provider.(DownloadVariant); ok {
+		variant = describer.DownloadVariant()
+	}
+
+	cacheable := nil != cache && "" != providerName && false == isUncacheable(provider)
+
+	pluginFileTar := ""
+	if cacheable {
+		if entry, err := cache.Get(hostname, variant, releaseVersion); err == nil {
+			pluginFileTar = filepath.Join(tempDirectory, entry.File)
+			if _, err = copyCacheFile(entry.FilePath(), pluginFileTar); err != nil {
+				pluginFileTar = ""
+			}
+		}
+	}
+
+	downloaded := false
+	if "" == pluginFileTar {
+		if pluginFileTar, err = releaseVersion.download(provider, tempDirectory); err != nil {
+			_ = os.RemoveAll(tempDirectory)
+
+			return ReleaseAssets{}, err
+		}
+
+		downloaded = true
+	}
+
+	if nil != verify {
+		if err = verify(pluginFileTar); err != nil {
+			_ = os.RemoveAll(tempDirectory)
+
+			return ReleaseAssets{}, err
+		}
+	}
+
+	if cacheable && downloaded {
+		_, _ = cache.Put(hostname, providerName, variant, releaseVersion, pluginFileTar)
+	}
+
+	prebuilt := ""
+	if "" != releaseAssetPattern(provider, releaseVersion) {
+		prebuilt = releaseVersion.Name
+	}
+
+	asset, err := NewReleaseAssetsWith(
+		ReleaseAssetsWithName(releaseVersion.NameWithOrganization()),
+		ReleaseAssetsWithVersion(releaseVersion.VersionWithOutV()),
+		ReleaseAssetsWithSourceTarFile(pluginFileTar),
+		ReleaseAssetsWithUntarFilePath(tempDirectory),
+		ReleaseAssetsWithManifest(releaseVersion.Manifest()),
+		ReleaseAssetsWithPrebuilt(prebuilt),
+	)
+	if err != nil {
+		_ = os.RemoveAll(tempDirectory)
+
+		return ReleaseAssets{}, err
+	}
+
+	return asset, nil
+}
+
+func (releaseVersion *ReleaseVersion) download(provider ReleasesProvider, tempDirectory string) (string, error) {
+	err := provider.Download(releaseVersion, tempDirectory)
 	if err != nil {
-		return ReleaseAssets{}, errors.New(fmt.Sprintf("Error Downloading Plugin executing cmd from provider %s", provider))
+		return "", fmt.Errorf("Error Downloading Plugin executing cmd from provider %s: %w", provider, err)
 	}
 
 	dirFiles, err := ioutil.ReadDir(tempDirectory)
 	if err != nil {
-		return ReleaseAssets{}, errors.New(fmt.Sprintf("Error Downloading Plugin ioutil.ReadDir failed at %s", tempDirectory))
+		return "", errors.New(fmt.Sprintf("Error Downloading Plugin ioutil.ReadDir failed at %s", tempDirectory))
 	}
 
-	var pluginFileTar string
+	return downloadedAsset(tempDirectory, dirFiles)
+}
+
+// downloadedAsset expects the provider to have downloaded exactly one file
+func downloadedAsset(tempDirectory string, dirFiles []os.FileInfo) (string, error) {
+	names := make([]string, 0, len(dirFiles))
 	for _, f := range dirFiles {
-		pluginFileTar = filepath.Join(tempDirectory, f.Name())
+		if false == f.IsDir() {
+			names = append(names, f.Name())
+		}
 	}
 
-	return NewReleaseAssets(releaseVersion.NameWithOrganization(), releaseVersion.VersionWithOutV(), pluginFileTar, tempDirectory), nil
+	switch len(names) {
+	case 0:
+		return "", fmt.Errorf("%w: nothing downloaded into %s", ErrReleaseAssetNotFound, tempDirectory)
+	case 1:
+		return filepath.Join(tempDirectory, names[0]), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrAmbiguousReleaseAsset, strings.Join(names, ", "))
+	}
+}
+
+// PackageInstallName is the directory a package is installed in, organization-name. The slashes of nested groups
+// and hostname prefixes are escaped so group/sub with a name package and group with a sub/name one stay apart
+func PackageInstallName(organization string, name string) string {
+	return fmt.Sprintf("%s-%s", strings.ReplaceAll(organization, "/", "%2F"), name)
 }
 
 func (releaseVersion *ReleaseVersion) NameWithOrganization() string {
-	return fmt.Sprintf("%s-%s", releaseVersion.Organization, releaseVersion.Name)
+	return PackageInstallName(releaseVersion.Organization, releaseVersion.Name)
 }
 
-func (releaseVersion *ReleaseVersion) DownloadAndInstallAsset(provider ReleasesProvider, releaseDir string) error {
+// DownloadAndInstallAsset refuses to install the asset when any verifier fails
+func (releaseVersion *ReleaseVersion) DownloadAndInstallAsset(provider ReleasesProvider, releaseDir string, verifiers ...AssetVerifier) error {
 	var asset ReleaseAssets
 	var err error
 
@@ -166,6 +343,12 @@ func (releaseVersion *ReleaseVersion) DownloadAndInstallAsset(provider ReleasesP
 		return err
 	}
 
+	if err = releaseVersion.VerifyAsset(asset, verifiers...); err != nil {
+		_ = asset.Remove()
+
+		return err
+	}
+
 	err = releaseVersion.InstallAsset(asset, releaseDir)
 
 	return err
@@ -173,7 +356,7 @@ func (releaseVersion *ReleaseVersion) DownloadAndInstallAsset(provider ReleasesP
 
 func (releaseVersion *ReleaseVersion) InstallAsset(asset ReleaseAssets, releaseDir string) error {
 	if false == releaseVersion.HasPackageMetadata(asset.DecompressPath()) {
-		return errors.New(fmt.Sprintf("Error Package Metadata not found at %s", filepath.Join(asset.DecompressPath(), releaseVersion.Manifest())))
+		return fmt.Errorf("Error Package Metadata not found at %s: %w", filepath.Join(asset.DecompressPath(), releaseVersion.Manifest()), ErrPackageManifestNotFound)
 	}
 
 	packageMetadata := releaseVersion.MustPackageMetadata(asset.DecompressPath())
@@ -187,7 +370,7 @@ func (releaseVersion *ReleaseVersion) InstallAsset(asset ReleaseAssets, releaseD
 }
 
 func (releaseVersion *ReleaseVersion) InstallAssetWithName(name string, asset ReleaseAssets, releaseDir string) error {
-	return releaseVersion.InstallAsset(asset.CopyWithName(fmt.Sprintf("%s-%s", releaseVersion.Organization, name)), releaseDir)
+	return releaseVersion.InstallAsset(asset.CopyWithName(PackageInstallName(releaseVersion.Organization, name)), releaseDir)
 }
 
 func (releaseVersion *ReleaseVersion) SetVersion(version string) {
@@ -275,3 +458,42 @@ func NewReleaseLatestVersion(organization string, name string, finder ReleaseVer
 
 	return newReleaseVersion, nil
 }
+
+func NewReleaseMatchingVersion(organization string, name string, constraint string, finder ReleaseVersionFinder) (ReleaseVersion, error) {
+	versionConstraint, err := NewVersionConstraint(constraint)
+	if err != nil {
+		return ReleaseVersion{}, err
+	}
+
+	versions, err := finder.List(organization, name)
+	if err != nil {
+		return ReleaseVersion{}, errors.New(fmt.Sprintf("Cant list release versions of %s/%s", organization, name))
+	}
+
+	version, err := versionConstraint.Highest(versions)
+	if err != nil {
+		return ReleaseVersion{}, fmt.Errorf("%s/%s: %w", organization, name, err)
+	}
+
+	return NewReleaseVersionWith(
+		ReleaseVersionWithOrganization(organization),
+		ReleaseVersionWithName(name),
+		ReleaseVersionWithVersion(version),
+	)
+}
+
+// ResolveReleaseVersion turns latest and version constraints into a concrete release tag, exact tags are kept
+func ResolveReleaseVersion(fqp FullyQualifyPackage, finder ReleaseVersionFinder) (ReleaseVersion, error) {
+	switch {
+	case "" == fqp.Version() || "latest" == fqp.Version():
+		return NewReleaseLatestVersion(fqp.Organization(), fqp.Name(), finder)
+	case fqp.IsVersionConstraint():
+		return NewReleaseMatchingVersion(fqp.Organization(), fqp.Name(), fqp.Version(), finder)
+	default:
+		return NewReleaseVersionWith(
+			ReleaseVersionWithOrganization(fqp.Organization()),
+			ReleaseVersionWithName(fqp.Name()),
+			ReleaseVersionWithVersion(fqp.Version()),
+		)
+	}
+}
@@ -1,21 +1,30 @@
 package repository
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
-	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
+var (
+	ErrPackageManifestNotFound = errors.New("package manifest not found in archive")
+	ErrAmbiguousPackageLayout  = errors.New("archive contains several package roots")
+)
+
 type ReleaseAssets struct {
 	name           string
 	version        string
 	sourceTarFile  string
 	untarFilesPath string
 	packageFolder  string
+	manifest       string
+	extractLimits  *ExtractLimits
+	prebuilt       string
 }
 
 func NewReleaseAssetsWith(options ...func(*ReleaseAssets) error) (ReleaseAssets, error) {
@@ -28,31 +37,99 @@ func NewReleaseAssetsWith(options ...func(*ReleaseAssets) error) (ReleaseAssets,
 		}
 	}
 
-	err := files.UnTargzAll(releaseAssets.sourceTarFile, releaseAssets.untarFilesPath)
+	limits := DefaultExtractLimits
+	if nil != releaseAssets.extractLimits {
+		limits = *releaseAssets.extractLimits
+	}
+
+	if "" == releaseAssets.manifest {
+		releaseAssets.manifest = DefaultPackageFile
+	}
+
+	err := ExtractArchiveWith(releaseAssets.sourceTarFile, releaseAssets.untarFilesPath, limits)
+	if errors.Is(err, ErrUnknownArchiveFormat) && "" != releaseAssets.prebuilt {
+		releaseAssets.packageFolder, err = releaseAssets.unpackBinary(limits)
+		if err != nil {
+			return ReleaseAssets{}, err
+		}
+
+		return *releaseAssets, nil
+	}
+
+	if err != nil {
+		return ReleaseAssets{}, fmt.Errorf("Error Downloading Plugin decompressing file %s in %s: %w", releaseAssets.sourceTarFile, releaseAssets.untarFilesPath, err)
+	}
+
+	releaseAssets.packageFolder, err = findPackageFolder(releaseAssets.untarFilesPath, releaseAssets.manifest)
+	if errors.Is(err, ErrPackageManifestNotFound) && "" != releaseAssets.prebuilt {
+		releaseAssets.packageFolder, err = singleTopLevelFolder(releaseAssets.untarFilesPath, releaseAssets.sourceTarFile)
+	}
 
 	if err != nil {
-		return ReleaseAssets{}, errors.New(fmt.Sprintf("Error Downloading Plugin decompressing file %s in %s", releaseAssets.sourceTarFile, releaseAssets.untarFilesPath))
+		return ReleaseAssets{}, err
 	}
 
-	err = filepath.Walk(releaseAssets.untarFilesPath, func(path string, info os.FileInfo, err error) error {
-		if path == releaseAssets.untarFilesPath {
+	return *releaseAssets, nil
+}
+
+// findPackageFolder returns the folder holding manifest relative to root, "" for flat archives.
+// The shallowest manifest wins so bundled dependencies don't count, several at that depth is ambiguous
+func findPackageFolder(root string, manifest string) (string, error) {
+	var candidates []string
+	depth := -1
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && ".git" == info.Name() {
+			return filepath.SkipDir
+		}
+
+		if false == info.Mode().IsRegular() || manifest != info.Name() {
 			return nil
 		}
 
-		if info.IsDir() {
-			releaseAssets.packageFolder = filepath.Base(path)
+		folder, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+
+		if "." == folder {
+			folder = ""
+		}
 
-			return io.EOF
+		folderDepth := 0
+		if "" != folder {
+			folderDepth = len(strings.Split(folder, string(filepath.Separator)))
 		}
 
-		return err
+		switch {
+		case -1 == depth || folderDepth < depth:
+			depth = folderDepth
+			candidates = []string{folder}
+		case folderDepth == depth:
+			candidates = append(candidates, folder)
+		}
+
+		return nil
 	})
 
-	if err != nil && err != io.EOF {
-		return ReleaseAssets{}, err
+	if err != nil {
+		return "", err
 	}
 
-	return *releaseAssets, nil
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrPackageManifestNotFound, manifest)
+	case 1:
+		return candidates[0], nil
+	default:
+		sort.Strings(candidates)
+
+		return "", fmt.Errorf("%w: %s found in %s", ErrAmbiguousPackageLayout, manifest, strings.Join(candidates, ", "))
+	}
 }
 
 func NewReleaseAssets(name string, version string, sourceTarFile string, untarFilesPath string) ReleaseAssets {
@@ -94,14 +171,132 @@ func ReleaseAssetsWithUntarFilePath(untarFilesPath string) func(*ReleaseAssets)
 	}
 }
 
+func ReleaseAssetsWithManifest(manifest string) func(*ReleaseAssets) error {
+	return func(p *ReleaseAssets) error {
+		p.manifest = manifest
+		return nil
+	}
+}
+
+func ReleaseAssetsWithExtractLimits(limits ExtractLimits) func(*ReleaseAssets) error {
+	return func(p *ReleaseAssets) error {
+		p.extractLimits = &limits
+		return nil
+	}
+}
+
+// ReleaseAssetsWithPrebuilt marks a release asset downloaded by an asset pattern. Its archive may have no manifest,
+// the files are then installed with --metadataJson, and a single binary asset is installed as binaryName
+func ReleaseAssetsWithPrebuilt(binaryName string) func(*ReleaseAssets) error {
+	return func(p *ReleaseAssets) error {
+		p.prebuilt = binaryName
+		return nil
+	}
+}
+
+// unpackBinary copies a prebuilt binary that isn't an archive into a folder of its own with a manifest linking it
+// as the prebuilt binary name
+func (asset *ReleaseAssets) unpackBinary(limits ExtractLimits) (string, error) {
+	info, err := os.Stat(asset.sourceTarFile)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Error can't open file %s", asset.sourceTarFile))
+	}
+
+	if limits.MaxTotalSize > 0 && info.Size() > limits.MaxTotalSize {
+		return "", &ArchiveEntryError{Entry: filepath.Base(asset.sourceTarFile), Err: ErrArchiveTooLarge}
+	}
+
+	if err = os.MkdirAll(asset.untarFilesPath, 0755); err != nil {
+		return "", errors.New(fmt.Sprintf("Error Creating dir %s", asset.untarFilesPath))
+	}
+
+	folder, err := os.MkdirTemp(asset.untarFilesPath, "binary-")
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Error Creating dir %s", asset.untarFilesPath))
+	}
+
+	binary := filepath.Join(folder, asset.prebuilt)
+	if _, err = copyCacheFile(asset.sourceTarFile, binary); err != nil {
+		return "", err
+	}
+
+	if err = os.Chmod(binary, 0755); err != nil {
+		return "", errors.New(fmt.Sprintf("Error can't make %s executable", binary))
+	}
+
+	metadata, err := NewPackageInstallerWith(
+		PackageInstallerWithName(asset.prebuilt),
+		PackageInstallerWithVersion(asset.version),
+		PackageInstallerWithScripts([]string{asset.prebuilt}),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := json.MarshalIndent(metadata, "", " ")
+	if err != nil {
+		return "", err
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(folder, asset.manifest), content, 0644); err != nil {
+		return "", errors.New(fmt.Sprintf("Error Creating manifest file %s", filepath.Join(folder, asset.manifest)))
+	}
+
+	return filepath.Base(folder), nil
+}
+
+// singleTopLevelFolder is used for prebuilt assets without a manifest, the files are installed with --metadataJson
+func singleTopLevelFolder(root string, sourceTarFile string) (string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return "", err
+	}
+
+	var folder string
+	count := 0
+	for _, entry := range entries {
+		if filepath.Join(root, entry.Name()) == filepath.Clean(sourceTarFile) {
+			continue
+		}
+
+		count++
+		if entry.IsDir() {
+			folder = entry.Name()
+		}
+	}
+
+	if 1 != count {
+		return "", nil
+	}
+
+	return folder, nil
+}
+
 func (asset *ReleaseAssets) PackageFolder() string {
 	return asset.packageFolder
 }
 
+func (asset *ReleaseAssets) SourceTarFile() string {
+	return asset.sourceTarFile
+}
+
+func (asset *ReleaseAssets) Sha256() (string, error) {
+	return FileSha256(asset.sourceTarFile)
+}
+
 func (asset *ReleaseAssets) DecompressPath() string {
 	return filepath.Join(asset.untarFilesPath, asset.PackageFolder())
 }
 
+// Remove deletes the decompressed files, the downloaded archive lives in the same directory when it came from DownloadAsset
+func (asset *ReleaseAssets) Remove() error {
+	if "" == asset.untarFilesPath {
+		return nil
+	}
+
+	return os.RemoveAll(asset.untarFilesPath)
+}
+
 func (asset *ReleaseAssets) Install(metadata *PackageInstaller, releaseDir string) error {
 	err := metadata.Install(asset.DecompressPath(), filepath.Join(releaseDir, asset.name))
 
@@ -170,6 +365,9 @@ func (asset *ReleaseAssets) clone() ReleaseAssets {
 	clone.sourceTarFile = asset.sourceTarFile
 	clone.untarFilesPath = asset.untarFilesPath
 	clone.packageFolder = asset.packageFolder
+	clone.manifest = asset.manifest
+	clone.extractLimits = asset.extractLimits
+	clone.prebuilt = asset.prebuilt
 
 	return *clone
 }
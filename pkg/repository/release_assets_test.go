@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -66,3 +68,186 @@ func TestCloneWith(t *testing.T) {
 	assert.Equal(t, asset, asset.clone())
 	assert.Equal(t, expectedVersion, clone.version)
 }
+
+func TestRemove(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	asset := NewReleaseAssets("testName", "v1.1", "testdata/sourceTarFile.tar.gz", tempFolder)
+
+	_, err := os.Stat(asset.DecompressPath())
+	require.Nil(t, err)
+
+	require.Nil(t, asset.Remove())
+
+	_, err = os.Stat(tempFolder)
+	assert.True(t, os.IsNotExist(err))
+
+	empty := ReleaseAssets{}
+	assert.Nil(t, empty.Remove())
+}
+
+func TestPackageFolderDetection(t *testing.T) {
+	manifest := testArchiveEntry{body: `{"name": "tool", "version": "1.0.0"}`}
+	withName := func(name string) testArchiveEntry {
+		entry := manifest
+		entry.name = name
+
+		return entry
+	}
+
+	layouts := map[string]struct {
+		entries  []testArchiveEntry
+		manifest string
+		expected string
+	}{
+		"flat": {
+			entries:  []testArchiveEntry{withName("package.json"), {name: "tool.sh", body: "echo"}},
+			expected: "",
+		},
+		"several top level dirs": {
+			entries:  []testArchiveEntry{{name: "docs/README.md", body: "docs"}, withName("tool-1.0.0/package.json")},
+			expected: "tool-1.0.0",
+		},
+		"deeper manifest": {
+			entries:  []testArchiveEntry{{name: "tool-1.0.0/README.md", body: "readme"}, withName("tool-1.0.0/pkg/package.json")},
+			expected: filepath.Join("tool-1.0.0", "pkg"),
+		},
+		"bundled dependency manifests": {
+			entries:  []testArchiveEntry{withName("tool/package.json"), withName("tool/deps/other/package.json")},
+			expected: "tool",
+		},
+		"custom manifest": {
+			entries:  []testArchiveEntry{withName("tool/package.json"), withName("tool/sub/bpkg.json")},
+			manifest: "bpkg.json",
+			expected: filepath.Join("tool", "sub"),
+		},
+	}
+
+	for name, layout := range layouts {
+		tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+		defer os.RemoveAll(tempFolder)
+
+		archive := writeTestTarGz(t, tempFolder, layout.entries)
+
+		asset, err := NewReleaseAssetsWith(
+			ReleaseAssetsWithSourceTarFile(archive),
+			ReleaseAssetsWithUntarFilePath(filepath.Join(tempFolder, "untar")),
+			ReleaseAssetsWithManifest(layout.manifest),
+		)
+		require.Nil(t, err, name)
+		assert.Equal(t, layout.expected, asset.PackageFolder(), name)
+
+		manifestName := layout.manifest
+		if "" == manifestName {
+			manifestName = DefaultPackageFile
+		}
+
+		_, err = os.Stat(filepath.Join(asset.DecompressPath(), manifestName))
+		assert.Nil(t, err, name)
+	}
+}
+
+func TestPackageFolderDetectionErrors(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	archive := writeTestTarGz(t, tempFolder, []testArchiveEntry{
+		{name: "one/package.json", body: "{}"},
+		{name: "two/package.json", body: "{}"},
+	})
+
+	_, err := NewReleaseAssetsWith(
+		ReleaseAssetsWithSourceTarFile(archive),
+		ReleaseAssetsWithUntarFilePath(filepath.Join(tempFolder, "ambiguous")),
+	)
+	assert.True(t, errors.Is(err, ErrAmbiguousPackageLayout))
+	assert.Contains(t, err.Error(), "one, two")
+
+	_, err = NewReleaseAssetsWith(
+		ReleaseAssetsWithSourceTarFile(archive),
+		ReleaseAssetsWithUntarFilePath(filepath.Join(tempFolder, "missing")),
+		ReleaseAssetsWithManifest("bpkg.json"),
+	)
+	assert.True(t, errors.Is(err, ErrPackageManifestNotFound))
+}
+
+func TestPackageFolderWithoutManifest(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	layouts := map[string]struct {
+		entries  []testArchiveEntry
+		expected string
+	}{
+		"single folder": {
+			entries:  []testArchiveEntry{{name: "mytool_linux_amd64/mytool", body: "bin"}},
+			expected: "mytool_linux_amd64",
+		},
+		"flat": {
+			entries:  []testArchiveEntry{{name: "mytool", body: "bin"}, {name: "LICENSE", body: "license"}},
+			expected: "",
+		},
+	}
+
+	for name, layout := range layouts {
+		dir := filepath.Join(tempFolder, name)
+		require.Nil(t, os.MkdirAll(dir, 0755))
+
+		archive := writeTestTarGz(t, dir, layout.entries)
+
+		_, err := NewReleaseAssetsWith(
+			ReleaseAssetsWithSourceTarFile(archive),
+			ReleaseAssetsWithUntarFilePath(filepath.Join(dir, "source")),
+		)
+		assert.True(t, errors.Is(err, ErrPackageManifestNotFound), name)
+
+		asset, err := NewReleaseAssetsWith(
+			ReleaseAssetsWithSourceTarFile(archive),
+			ReleaseAssetsWithUntarFilePath(filepath.Join(dir, "prebuilt")),
+			ReleaseAssetsWithPrebuilt("mytool"),
+		)
+		require.Nil(t, err, name)
+		assert.Equal(t, layout.expected, asset.PackageFolder(), name)
+	}
+}
+
+func TestPrebuiltBinaryAsset(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	binary := filepath.Join(tempFolder, "mytool_linux_amd64")
+	require.Nil(t, os.WriteFile(binary, []byte("#!/bin/sh\necho mytool"), 0644))
+
+	_, err := NewReleaseAssetsWith(
+		ReleaseAssetsWithSourceTarFile(binary),
+		ReleaseAssetsWithUntarFilePath(filepath.Join(tempFolder, "source")),
+	)
+	assert.True(t, errors.Is(err, ErrUnknownArchiveFormat))
+
+	asset, err := NewReleaseAssetsWith(
+		ReleaseAssetsWithVersion("1.0.0"),
+		ReleaseAssetsWithSourceTarFile(binary),
+		ReleaseAssetsWithUntarFilePath(filepath.Join(tempFolder, "prebuilt")),
+		ReleaseAssetsWithPrebuilt("mytool"),
+	)
+	require.Nil(t, err)
+
+	info, err := os.Stat(filepath.Join(asset.DecompressPath(), "mytool"))
+	require.Nil(t, err)
+	assert.Equal(t, os.FileMode(0111), info.Mode().Perm()&0111)
+
+	metadata, err := NewPackageInstallerFromFileName(filepath.Join(asset.DecompressPath(), DefaultPackageFile))
+	require.Nil(t, err)
+	assert.Equal(t, "mytool", metadata.Name)
+	assert.Equal(t, "1.0.0", metadata.Version)
+	assert.Equal(t, []string{"mytool"}, metadata.Scripts)
+
+	_, err = NewReleaseAssetsWith(
+		ReleaseAssetsWithSourceTarFile(binary),
+		ReleaseAssetsWithUntarFilePath(filepath.Join(tempFolder, "limited")),
+		ReleaseAssetsWithPrebuilt("mytool"),
+		ReleaseAssetsWithExtractLimits(ExtractLimits{MaxTotalSize: 4}),
+	)
+	assert.True(t, errors.Is(err, ErrArchiveTooLarge))
+}
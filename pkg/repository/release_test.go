@@ -80,8 +80,10 @@ func TestReleaseVersionWithFQDN(t *testing.T) {
 			"organization//name",
 			"/organization/name",
 			"organization/name::",
-			"organization/name:^1.0",
-			"organization/name:~1.0",
+			"organization/name:^",
+			"organization/name:~1.0.0.0",
+			"organization/name:>=1.0<2.0",
+			"organization/name:^1.0 <2.0",
 			"organization/name:1-0",
 			"organization /name:1.0",
 			"organization/name :1.0",
@@ -176,3 +178,32 @@ func TestDownloadAssetWithMultipleInstallations(t *testing.T) {
 
 	assert.True(t, cloneWithName.IsInstalled(installDirB))
 }
+
+func TestResolveReleaseVersion(t *testing.T) {
+	finder := newMockReleaseVersionFinder()
+	finder.LatestFn = func(string, string) (string, error) {
+		return "v2.0.0", nil
+	}
+	finder.ListFn = func(string, string) ([]string, error) {
+		return []string{"v1.0.0", "v1.1.0", "v2.0.0"}, nil
+	}
+
+	expectations := map[string]string{
+		"org/name":        "v2.0.0",
+		"org/name:latest": "v2.0.0",
+		"org/name:^1.0":   "v1.1.0",
+		"org/name:v0.1":   "v0.1",
+	}
+
+	for fqp, expected := range expectations {
+		fqpVO, err := NewFullyQualifyPackage(fqp)
+		require.Nil(t, err)
+
+		releaseVersion, err := ResolveReleaseVersion(fqpVO, finder)
+		require.Nil(t, err)
+
+		assert.Equal(t, expected, releaseVersion.Version(), fqp)
+		assert.Equal(t, "org", releaseVersion.Organization)
+		assert.Equal(t, "name", releaseVersion.Name)
+	}
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidSemanticVersion   = errors.New("invalid semantic version")
+	ErrInvalidVersionConstraint = errors.New("invalid version constraint")
+	ErrNoMatchingReleaseVersion = errors.New("no release version matches constraint")
+
+	semanticVersionExpression = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([\w\.\-]+))?(?:\+[\w\.\-]+)?$`)
+	comparatorExpression      = regexp.MustCompile(`^(\^|~|>=|<=|>|<|=)?(v?\d+(?:\.\d+){0,2})$`)
+)
+
+type SemanticVersion struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	// parts holds how many numeric components were written, 1.2 has 2
+	parts int
+}
+
+func NewSemanticVersion(version string) (SemanticVersion, error) {
+	matches := semanticVersionExpression.FindStringSubmatch(strings.TrimSpace(version))
+	if nil == matches {
+		return SemanticVersion{}, fmt.Errorf("%w: %s", ErrInvalidSemanticVersion, version)
+	}
+
+	semanticVersion := SemanticVersion{Prerelease: matches[4], parts: 1}
+	semanticVersion.Major, _ = strconv.Atoi(matches[1])
+
+	if "" != matches[2] {
+		semanticVersion.Minor, _ = strconv.Atoi(matches[2])
+		semanticVersion.parts++
+	}
+
+	if "" != matches[3] {
+		semanticVersion.Patch, _ = strconv.Atoi(matches[3])
+		semanticVersion.parts++
+	}
+
+	return semanticVersion, nil
+}
+
+func (version SemanticVersion) Compare(other SemanticVersion) int {
+	for _, diff := range []int{version.Major - other.Major, version.Minor - other.Minor, version.Patch - other.Patch} {
+		if diff < 0 {
+			return -1
+		}
+
+		if diff > 0 {
+			return 1
+		}
+	}
+
+	switch {
+	case version.Prerelease == other.Prerelease:
+		return 0
+	case "" == version.Prerelease:
+		return 1
+	case "" == other.Prerelease:
+		return -1
+	case version.Prerelease < other.Prerelease:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func (version SemanticVersion) String() string {
+	if "" != version.Prerelease {
+		return fmt.Sprintf("%d.%d.%d-%s", version.Major, version.Minor, version.Patch, version.Prerelease)
+	}
+
+	return fmt.Sprintf("%d.%d.%d", version.Major, version.Minor, version.Patch)
+}
+
+type versionComparator struct {
+	operator string
+	version  SemanticVersion
+}
+
+func (comparator versionComparator) matches(version SemanticVersion) bool {
+	compare := version.Compare(comparator.version)
+
+	switch comparator.operator {
+	case ">=":
+		return compare >= 0
+	case "<=":
+		return compare <= 0
+	case ">":
+		return compare > 0
+	case "<":
+		return compare < 0
+	default:
+		return compare == 0
+	}
+}
+
+// VersionConstraint is a set of comparators that must all match, e.g. "^1.2", "~1.4.0" or ">=1.0 <2.0"
+type VersionConstraint struct {
+	constraint  string
+	comparators []versionComparator
+}
+
+func NewVersionConstraint(constraint string) (VersionConstraint, error) {
+	fields := strings.Fields(constraint)
+	if 0 == len(fields) {
+		return VersionConstraint{}, fmt.Errorf("%w: %s", ErrInvalidVersionConstraint, constraint)
+	}
+
+	versionConstraint := VersionConstraint{constraint: strings.Join(fields, " ")}
+
+	for _, field := range fields {
+		matches := comparatorExpression.FindStringSubmatch(field)
+		if nil == matches {
+			return VersionConstraint{}, fmt.Errorf("%w: %s", ErrInvalidVersionConstraint, constraint)
+		}
+
+		version, err := NewSemanticVersion(matches[2])
+		if err != nil {
+			return VersionConstraint{}, fmt.Errorf("%w: %s", ErrInvalidVersionConstraint, constraint)
+		}
+
+		switch matches[1] {
+		case "^":
+			versionConstraint.comparators = append(versionConstraint.comparators,
+				versionComparator{">=", version},
+				versionComparator{"<", caretUpperBound(version)},
+			)
+		case "~":
+			versionConstraint.comparators = append(versionConstraint.comparators,
+				versionComparator{">=", version},
+				versionComparator{"<", tildeUpperBound(version)},
+			)
+		default:
+			versionConstraint.comparators = append(versionConstraint.comparators, versionComparator{matches[1], version})
+		}
+	}
+
+	return versionConstraint, nil
+}
+
+// IsVersionConstraint reports whether version is a range rather than an exact tag or latest
+func IsVersionConstraint(version string) bool {
+	return strings.ContainsAny(version, "^~<>= ")
+}
+
+func caretUpperBound(version SemanticVersion) SemanticVersion {
+	switch {
+	case version.Major > 0 || version.parts == 1:
+		return SemanticVersion{Major: version.Major + 1}
+	case version.Minor > 0 || version.parts == 2:
+		return SemanticVersion{Minor: version.Minor + 1}
+	default:
+		return SemanticVersion{Patch: version.Patch + 1}
+	}
+}
+
+func tildeUpperBound(version SemanticVersion) SemanticVersion {
+	if version.parts == 1 {
+		return SemanticVersion{Major: version.Major + 1}
+	}
+
+	return SemanticVersion{Major: version.Major, Minor: version.Minor + 1}
+}
+
+// Matches never accepts prereleases so ranges only move across stable releases
+func (constraint VersionConstraint) Matches(version SemanticVersion) bool {
+	if "" != version.Prerelease {
+		return false
+	}
+
+	for _, comparator := range constraint.comparators {
+		if false == comparator.matches(version) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Highest returns the highest tag matching the constraint, tags that aren't semantic versions are ignored
+func (constraint VersionConstraint) Highest(tags []string) (string, error) {
+	var highestTag string
+	var highest SemanticVersion
+
+	for _, tag := range tags {
+		version, err := NewSemanticVersion(tag)
+		if err != nil || false == constraint.Matches(version) {
+			continue
+		}
+
+		if "" == highestTag || version.Compare(highest) > 0 {
+			highestTag, highest = tag, version
+		}
+	}
+
+	if "" == highestTag {
+		return "", fmt.Errorf("%w: %s", ErrNoMatchingReleaseVersion, constraint.constraint)
+	}
+
+	return highestTag, nil
+}
+
+func (constraint VersionConstraint) String() string {
+	return constraint.constraint
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewSemanticVersion(t *testing.T) {
+	valid := map[string]string{
+		"v1.2.3":       "1.2.3",
+		"1.2":          "1.2.0",
+		"v2":           "2.0.0",
+		"1.0.0-rc.1":   "1.0.0-rc.1",
+		"1.0.0+build1": "1.0.0",
+	}
+
+	for version, expected := range valid {
+		semanticVersion, err := NewSemanticVersion(version)
+		require.Nil(t, err, version)
+		assert.Equal(t, expected, semanticVersion.String())
+	}
+
+	for _, version := range []string{"latest", "1.2.3.4", "v", "release-1"} {
+		_, err := NewSemanticVersion(version)
+		assert.True(t, errors.Is(err, ErrInvalidSemanticVersion), version)
+	}
+}
+
+func TestSemanticVersionCompare(t *testing.T) {
+	ordered := []string{"0.0.1", "0.1.0", "1.0.0-alpha", "1.0.0-beta", "1.0.0", "1.0.1", "1.10.0", "2.0.0"}
+
+	for i := 1; i < len(ordered); i++ {
+		lower, _ := NewSemanticVersion(ordered[i-1])
+		higher, _ := NewSemanticVersion(ordered[i])
+
+		assert.Equal(t, -1, lower.Compare(higher), ordered[i])
+		assert.Equal(t, 1, higher.Compare(lower), ordered[i])
+		assert.Equal(t, 0, higher.Compare(higher), ordered[i])
+	}
+}
+
+func TestVersionConstraintHighest(t *testing.T) {
+	tags := []string{"v0.1.0", "v0.1.5", "v0.2.0", "v1.1.0", "v1.2.0", "v1.2.9", "v1.4.0", "v1.4.7", "v1.5.0", "v2.0.0-rc.1", "v2.0.0", "nightly"}
+
+	expectations := map[string]string{
+		"^1.2":       "v1.5.0",
+		"^1":         "v1.5.0",
+		"~1.4.0":     "v1.4.7",
+		"~1.2":       "v1.2.9",
+		"~1":         "v1.5.0",
+		">=1.0 <2.0": "v1.5.0",
+		">1.4.7":     "v2.0.0",
+		"<=1.2.0":    "v1.2.0",
+		"=v1.1.0":    "v1.1.0",
+		"^0.1":       "v0.1.5",
+		"^0.1.0":     "v0.1.5",
+		">=1.0":      "v2.0.0",
+	}
+
+	for constraint, expected := range expectations {
+		versionConstraint, err := NewVersionConstraint(constraint)
+		require.Nil(t, err, constraint)
+
+		actual, err := versionConstraint.Highest(tags)
+		require.Nil(t, err, constraint)
+		assert.Equal(t, expected, actual, constraint)
+	}
+
+	versionConstraint, err := NewVersionConstraint("^3.0")
+	require.Nil(t, err)
+
+	_, err = versionConstraint.Highest(tags)
+	assert.True(t, errors.Is(err, ErrNoMatchingReleaseVersion))
+
+	for _, constraint := range []string{"", "^", "1.0-", "=>1.0", "latest"} {
+		_, err = NewVersionConstraint(constraint)
+		assert.True(t, errors.Is(err, ErrInvalidVersionConstraint), constraint)
+	}
+}
+
+func TestNewReleaseMatchingVersion(t *testing.T) {
+	finder := newMockReleaseVersionFinder()
+
+	t.Run("Can't list versions", func(t *testing.T) {
+		finder.ListFn = func(string, string) ([]string, error) {
+			return []string{}, errors.New("cant list versions")
+		}
+
+		_, err := NewReleaseMatchingVersion("dummy", "dum", "^1.0", finder)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Highest matching version", func(t *testing.T) {
+		finder.ListFn = func(organization string, name string) ([]string, error) {
+			assert.Equal(t, "dummy", organization)
+			assert.Equal(t, "dum", name)
+
+			return []string{"v1.0.0", "v1.3.1", "v2.0.0", "v1.3.0"}, nil
+		}
+
+		releaseVersion, err := NewReleaseMatchingVersion("dummy", "dum", "^1.0", finder)
+		require.Nil(t, err)
+		assert.Equal(t, "v1.3.1", releaseVersion.Version())
+		assert.Equal(t, "dummy/dum:v1.3.1", releaseVersion.String())
+
+		_, err = NewReleaseMatchingVersion("dummy", "dum", "^3.0", finder)
+		assert.True(t, errors.Is(err, ErrNoMatchingReleaseVersion))
+
+		_, err = NewReleaseMatchingVersion("dummy", "dum", "latest", finder)
+		assert.True(t, errors.Is(err, ErrInvalidVersionConstraint))
+	})
+}
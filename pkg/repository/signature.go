@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const SignatureExtension = ".sig"
+
+var (
+	ErrInvalidPublicKey   = errors.New("invalid ed25519 public key")
+	ErrInvalidSignature   = errors.New("invalid ed25519 signature")
+	ErrSignatureNotFound  = errors.New("release publishes no signature")
+	ErrSignatureMismatch  = errors.New("signature doesn't verify against any trusted key")
+	ErrNoTrustedPublisher = errors.New("no trusted keys for organization")
+)
+
+// SignaturesProvider downloads the detached signature of assetName, named assetName.sig, into downloadDir
+type SignaturesProvider interface {
+	DownloadSignature(releaseVersion *ReleaseVersion, assetName string, downloadDir string) error
+}
+
+// AssetVerifier runs between DownloadAsset and InstallAsset, an error refuses the install
+type AssetVerifier interface {
+	VerifyAsset(releaseVersion *ReleaseVersion, asset ReleaseAssets) error
+}
+
+type TrustStore struct {
+	keys     map[string][]ed25519.PublicKey
+	unsigned map[string]bool
+}
+
+// NewTrustStoreFromConfig reads every key file of config, keys are local files so verification works offline
+func NewTrustStoreFromConfig(config *Config) (*TrustStore, error) {
+	trustStore := &TrustStore{
+		keys:     make(map[string][]ed25519.PublicKey),
+		unsigned: make(map[string]bool),
+	}
+
+	for _, organization := range config.UnsignedOrganizations {
+		trustStore.unsigned[organization] = true
+	}
+
+	for organization, keyFiles := range config.TrustedKeys {
+		for _, keyFile := range keyFiles {
+			path := config.resolvePath(keyFile)
+
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("Error can't open file %s", path))
+			}
+
+			key, err := ParsePublicKey(data)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", err, path)
+			}
+
+			trustStore.keys[organization] = append(trustStore.keys[organization], key)
+		}
+	}
+
+	return trustStore, nil
+}
+
+func (trustStore *TrustStore) Keys(organization string) []ed25519.PublicKey {
+	return trustStore.keys[organization]
+}
+
+// ParsePublicKey accepts a PEM encoded PKIX key as written by openssl, a base64 encoded raw key or the raw 32 bytes
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); nil != block {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, ErrInvalidPublicKey
+		}
+
+		publicKey, ok := key.(ed25519.PublicKey)
+		if false == ok {
+			return nil, ErrInvalidPublicKey
+		}
+
+		return publicKey, nil
+	}
+
+	raw := decodeBase64OrRaw(data)
+	if ed25519.PublicKeySize != len(raw) {
+		return nil, ErrInvalidPublicKey
+	}
+
+	return ed25519.PublicKey(raw), nil
+}
+
+// ParseSignature accepts a base64 encoded signature or the raw 64 bytes
+func ParseSignature(data []byte) ([]byte, error) {
+	raw := decodeBase64OrRaw(data)
+	if ed25519.SignatureSize != len(raw) {
+		return nil, ErrInvalidSignature
+	}
+
+	return raw, nil
+}
+
+func decodeBase64OrRaw(data []byte) []byte {
+	decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return data
+	}
+
+	return decoded
+}
+
+type SignatureVerifier struct {
+	provider   ReleasesProvider
+	trustStore *TrustStore
+}
+
+func NewSignatureVerifier(provider ReleasesProvider, trustStore *TrustStore) *SignatureVerifier {
+	return &SignatureVerifier{
+		provider:   provider,
+		trustStore: trustStore,
+	}
+}
+
+// VerifyAsset requires a valid signature for organizations with trusted keys, organizations without keys are
+// refused unless the config lists them in unsignedOrganizations. Organizations of forges on a non default host are
+// looked up as host/organization, the same organization on github.com doesn't vouch for them
+func (verifier *SignatureVerifier) VerifyAsset(releaseVersion *ReleaseVersion, asset ReleaseAssets) error {
+	providerName, hostname := DescribeProvider(verifier.provider)
+	organization := PackageOrganization(providerName, hostname, releaseVersion.Organization)
+
+	keys := verifier.trustStore.Keys(organization)
+
+	if 0 == len(keys) {
+		if verifier.trustStore.unsigned[organization] {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %s, add its keys to trustedKeys or it to unsignedOrganizations of the config",
+			ErrNoTrustedPublisher, organization)
+	}
+
+	assetName := filepath.Base(asset.SourceTarFile())
+
+	signaturesProvider, ok := verifier.provider.(SignaturesProvider)
+	if false == ok {
+		return fmt.Errorf("%w: %s%s", ErrSignatureNotFound, assetName, SignatureExtension)
+	}
+
+	signatureDir, err := os.MkdirTemp("", "temp-signature-folder")
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error Creating temporal dir %s", signatureDir))
+	}
+
+	defer os.RemoveAll(signatureDir)
+
+	if err = signaturesProvider.DownloadSignature(releaseVersion, assetName, signatureDir); err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(signatureDir, assetName+SignatureExtension))
+	if err != nil {
+		return fmt.Errorf("%w: %s%s", ErrSignatureNotFound, assetName, SignatureExtension)
+	}
+
+	signature, err := ParseSignature(data)
+	if err != nil {
+		return fmt.Errorf("%w: %s%s", err, assetName, SignatureExtension)
+	}
+
+	return VerifySignature(keys, asset.SourceTarFile(), signature)
+}
+
+func VerifySignature(keys []ed25519.PublicKey, filePath string, signature []byte) error {
+	message, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error can't open file %s", filePath))
+	}
+
+	for _, key := range keys {
+		if ed25519.Verify(key, message, signature) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrSignatureMismatch, filepath.Base(filePath))
+}
+
+// VerifyAsset runs every verifier against the downloaded asset before it is installed
+func (releaseVersion *ReleaseVersion) VerifyAsset(asset ReleaseAssets, verifiers ...AssetVerifier) error {
+	for _, verifier := range verifiers {
+		if err := verifier.VerifyAsset(releaseVersion, asset); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
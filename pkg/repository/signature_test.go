@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestConfig(t *testing.T, dir string, publicKey ed25519.PublicKey, unsigned ...string) *Config {
+	pkix, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.Nil(t, err)
+
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, "keys"), 0755))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "keys", "org.pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}), 0644))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "keys", "org.pub"), []byte(base64.StdEncoding.EncodeToString(publicKey)+"\n"), 0644))
+
+	configFile := filepath.Join(dir, DefaultConfigFile)
+	unsignedOrganizations, err := json.Marshal(unsigned)
+	require.Nil(t, err)
+
+	content := fmt.Sprintf(`{"trustedKeys": {"org": ["keys/org.pem", "keys/org.pub"]}, "unsignedOrganizations": %s}`, unsignedOrganizations)
+	require.Nil(t, os.WriteFile(configFile, []byte(content), 0644))
+
+	config, err := NewConfigFromFileName(configFile)
+	require.Nil(t, err)
+
+	return config
+}
+
+func TestNewConfigFromFileName(t *testing.T) {
+	config, err := NewConfigFromFileName("testdata/not_found.json")
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(config.TrustedKeys))
+
+	_ = os.Setenv(ConfigPathEnv, "/tmp/bpkg-config.json")
+	defer os.Unsetenv(ConfigPathEnv)
+
+	assert.Equal(t, "/tmp/bpkg-config.json", DefaultConfigPath())
+}
+
+func TestParsePublicKeyAndSignature(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	require.Nil(t, err)
+
+	parsed, err := ParsePublicKey(publicKey)
+	require.Nil(t, err)
+	assert.Equal(t, publicKey, parsed)
+
+	_, err = ParsePublicKey([]byte("not a key"))
+	assert.True(t, errors.Is(err, ErrInvalidPublicKey))
+
+	_, err = ParseSignature([]byte(base64.StdEncoding.EncodeToString([]byte("short"))))
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+}
+
+func TestSignatureVerifier(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.Nil(t, err)
+
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	require.Nil(t, err)
+
+	trustStore, err := NewTrustStoreFromConfig(writeTestConfig(t, tempFolder, publicKey))
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(trustStore.Keys("org")))
+
+	asset := NewReleaseAssets("org-assert.sh", "v1.1", "testdata/sourceTarFile.tar.gz", filepath.Join(tempFolder, "untar"))
+	message, err := ioutil.ReadFile(asset.SourceTarFile())
+	require.Nil(t, err)
+
+	signed := func(key ed25519.PrivateKey) *mockSignaturesReleasesProvider {
+		return &mockSignaturesReleasesProvider{
+			mockReleasesProvider: newMockReleasesProvider(),
+			signature:            []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, message))),
+		}
+	}
+
+	releaseVersion := NewReleaseVersion("org", "assert.sh", "v1.1")
+
+	assert.Nil(t, releaseVersion.VerifyAsset(asset, NewSignatureVerifier(signed(privateKey), trustStore)))
+
+	err = releaseVersion.VerifyAsset(asset, NewSignatureVerifier(signed(otherKey), trustStore))
+	assert.True(t, errors.Is(err, ErrSignatureMismatch))
+
+	unsigned := &mockSignaturesReleasesProvider{mockReleasesProvider: newMockReleasesProvider()}
+	err = releaseVersion.VerifyAsset(asset, NewSignatureVerifier(unsigned, trustStore))
+	assert.True(t, errors.Is(err, ErrSignatureNotFound))
+
+	err = releaseVersion.VerifyAsset(asset, NewSignatureVerifier(newMockReleasesProvider(), trustStore))
+	assert.True(t, errors.Is(err, ErrSignatureNotFound))
+
+	otherOrganization := NewReleaseVersion("other", "assert.sh", "v1.1")
+	err = otherOrganization.VerifyAsset(asset, NewSignatureVerifier(unsigned, trustStore))
+	assert.True(t, errors.Is(err, ErrNoTrustedPublisher))
+
+	emptyTrustStore, err := NewTrustStoreFromConfig(&Config{})
+	require.Nil(t, err)
+
+	// out of the box no organization is trusted, the error tells how to trust or opt out of it
+	err = releaseVersion.VerifyAsset(asset, NewSignatureVerifier(signed(privateKey), emptyTrustStore))
+	assert.True(t, errors.Is(err, ErrNoTrustedPublisher))
+	assert.Equal(t, "no trusted keys for organization: org, add its keys to trustedKeys or it to unsignedOrganizations of the config", err.Error())
+
+	optOutTrustStore, err := NewTrustStoreFromConfig(writeTestConfig(t, tempFolder, publicKey, "other", "org"))
+	require.Nil(t, err)
+
+	assert.Nil(t, otherOrganization.VerifyAsset(asset, NewSignatureVerifier(unsigned, optOutTrustStore)))
+
+	err = releaseVersion.VerifyAsset(asset, NewSignatureVerifier(unsigned, optOutTrustStore))
+	assert.True(t, errors.Is(err, ErrSignatureNotFound))
+
+	// keys and opt outs of org on github.com don't apply to org on another host
+	enterprise := &describedSignaturesProvider{mockSignaturesReleasesProvider: signed(privateKey), hostname: "ghe.corp.example"}
+	err = releaseVersion.VerifyAsset(asset, NewSignatureVerifier(enterprise, trustStore))
+	assert.True(t, errors.Is(err, ErrNoTrustedPublisher))
+	assert.Contains(t, err.Error(), "ghe.corp.example/org")
+
+	err = otherOrganization.VerifyAsset(asset, NewSignatureVerifier(enterprise, optOutTrustStore))
+	assert.True(t, errors.Is(err, ErrNoTrustedPublisher))
+
+	enterprise.hostname = GithubRepository
+	assert.Nil(t, releaseVersion.VerifyAsset(asset, NewSignatureVerifier(enterprise, trustStore)))
+}
+
+type describedSignaturesProvider struct {
+	*mockSignaturesReleasesProvider
+	hostname string
+}
+
+func (d *describedSignaturesProvider) ProviderName() string {
+	return GithubProviderName
+}
+
+func (d *describedSignaturesProvider) Hostname() string {
+	return d.hostname
+}
+
+func TestLocalProviderSignature(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	archive := filepath.Join(tempFolder, "assert.sh-1.1.tar.gz")
+	message, err := ioutil.ReadFile("testdata/sourceTarFile.tar.gz")
+	require.Nil(t, err)
+	require.Nil(t, os.WriteFile(archive, message, 0644))
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.Nil(t, err)
+
+	trustStore, err := NewTrustStoreFromConfig(writeTestConfig(t, tempFolder, publicKey))
+	require.Nil(t, err)
+
+	provider, err := NewLocalProvider(archive)
+	require.Nil(t, err)
+
+	releaseVersion := NewReleaseVersion("org", "assert.sh", "v1.1")
+	installDir := filepath.Join(tempFolder, "deps")
+
+	err = releaseVersion.DownloadAndInstallAsset(provider, installDir, NewSignatureVerifier(provider, trustStore))
+	assert.True(t, errors.Is(err, ErrSignatureNotFound))
+	assert.False(t, releaseVersion.IsInstalled(installDir))
+
+	require.Nil(t, os.WriteFile(archive+SignatureExtension, ed25519.Sign(privateKey, message), 0644))
+
+	require.Nil(t, releaseVersion.DownloadAndInstallAsset(provider, installDir, NewSignatureVerifier(provider, trustStore)))
+	assert.True(t, releaseVersion.IsInstalled(installDir))
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrPackageNotInstalled   = errors.New("package not installed")
+	ErrUpgradeRollbackFailed = errors.New("upgrade rollback failed")
+)
+
+// packageBackup moves an installed package and its bin links aside so they can be put back
+type packageBackup struct {
+	moves [][2]string
+}
+
+func (backup *packageBackup) move(src string, dst string) error {
+	if _, err := os.Lstat(src); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); nil != err {
+		return errors.New(fmt.Sprintf("Error Creating dir %s", filepath.Dir(dst)))
+	}
+
+	if err := os.Rename(src, dst); nil != err {
+		return errors.New(fmt.Sprintf("Error Moving %s to %s", src, dst))
+	}
+
+	backup.moves = append(backup.moves, [2]string{src, dst})
+
+	return nil
+}
+
+func (backup *packageBackup) save(installed *PackageInstaller, destDir string, backupDir string) error {
+	if err := backup.move(destDir, filepath.Join(backupDir, filepath.Base(destDir))); err != nil {
+		return err
+	}
+
+	for _, link := range installed.BinLinks(filepath.Dir(destDir)) {
+		if err := backup.move(link, filepath.Join(backupDir, installed.BinDir, filepath.Base(link))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (backup *packageBackup) restore() error {
+	var err error
+
+	for i := len(backup.moves) - 1; i >= 0; i-- {
+		original, saved := backup.moves[i][0], backup.moves[i][1]
+
+		if removeErr := os.RemoveAll(original); nil != removeErr {
+			err = errors.New(fmt.Sprintf("Error Restoring %s", original))
+
+			continue
+		}
+
+		if renameErr := os.Rename(saved, original); nil != renameErr {
+			err = errors.New(fmt.Sprintf("Error Restoring %s from %s", original, saved))
+		}
+	}
+
+	return err
+}
+
+// rollback restores the backup after cause, when that fails the backup is the only copy of the installed package so it
+// stays in backupDir and the error reports where
+func (backup *packageBackup) rollback(cause error, backupDir string) error {
+	if restoreErr := backup.restore(); restoreErr != nil {
+		return fmt.Errorf("%w: %s, %s, the previous version is kept at %s", ErrUpgradeRollbackFailed, cause,
+			restoreErr, backupDir)
+	}
+
+	return nil
+}
+
+// Upgrade installs metadata into a staging directory first and only swaps it with the installed package once that
+// succeeded, the installed files and bin links are restored when any step fails and kept in the staging directory
+// when they can't be
+func (asset *ReleaseAssets) Upgrade(metadata *PackageInstaller, releaseDir string) error {
+	destDir := filepath.Join(releaseDir, asset.name)
+
+	installed, err := NewPackageInstallerFromFileName(filepath.Join(destDir, metadata.Manifest))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPackageNotInstalled, destDir)
+	}
+
+	stagingDir, err := os.MkdirTemp(releaseDir, ".upgrade-")
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error Creating staging dir at %s", releaseDir))
+	}
+
+	backupDir := filepath.Join(stagingDir, "old")
+	keepBackup := false
+
+	defer func() {
+		if keepBackup {
+			_ = os.RemoveAll(filepath.Join(stagingDir, "new"))
+
+			return
+		}
+
+		_ = os.RemoveAll(stagingDir)
+	}()
+
+	stagedDir := filepath.Join(stagingDir, "new", asset.name)
+	if err = metadata.Install(asset.DecompressPath(), stagedDir); err != nil {
+		return errors.New(fmt.Sprintf("Error Upgrading Package staging install failed %s", err))
+	}
+
+	backup := new(packageBackup)
+	if err = backup.save(installed, destDir, backupDir); err != nil {
+		if rollbackErr := backup.rollback(err, backupDir); rollbackErr != nil {
+			keepBackup = true
+
+			return rollbackErr
+		}
+
+		return errors.New(fmt.Sprintf("Error Upgrading Package %s", err))
+	}
+
+	if err = os.Rename(stagedDir, destDir); nil == err {
+		err = metadata.Link(destDir)
+	}
+
+	if err != nil {
+		for _, link := range metadata.BinLinks(releaseDir) {
+			if linksInto(link, destDir) {
+				_ = os.Remove(link)
+			}
+		}
+
+		if rollbackErr := backup.rollback(err, backupDir); rollbackErr != nil {
+			keepBackup = true
+
+			return rollbackErr
+		}
+
+		return errors.New(fmt.Sprintf("Error Upgrading Package %s, previous version restored", err))
+	}
+
+	return nil
+}
+
+func linksInto(link string, dir string) bool {
+	target, err := os.Readlink(link)
+	if err != nil {
+		return false
+	}
+
+	if false == filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(link), target)
+	}
+
+	return strings.HasPrefix(filepath.Clean(target), filepath.Clean(dir)+string(filepath.Separator))
+}
+
+func (releaseVersion *ReleaseVersion) UpgradeAsset(asset ReleaseAssets, releaseDir string) error {
+	if false == releaseVersion.HasPackageMetadata(asset.DecompressPath()) {
+		return fmt.Errorf("Error Package Metadata not found at %s: %w", filepath.Join(asset.DecompressPath(), releaseVersion.Manifest()), ErrPackageManifestNotFound)
+	}
+
+	return asset.Upgrade(releaseVersion.MustPackageMetadata(asset.DecompressPath()), releaseDir)
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newUpgradeTestPackage(t *testing.T, version string, scripts []string) PackageInstaller {
+	packageInstaller, err := NewPackageInstallerWith(
+		PackageInstallerWithName("org-assert.sh"),
+		PackageInstallerWithVersion(version),
+		PackageInstallerWithFiles([]string{"assert.sh"}),
+		PackageInstallerWithScripts(scripts),
+	)
+	require.Nil(t, err)
+
+	return packageInstaller
+}
+
+func TestUpgrade(t *testing.T) {
+	tempFolder, _ := os.MkdirTemp("", "temp-test-folder")
+	defer os.RemoveAll(tempFolder)
+
+	installFolder, _ := os.MkdirTemp("", "temp-install-folder")
+	defer os.RemoveAll(installFolder)
+
+	asset := NewReleaseAssets("org-assert.sh", "v1.1", "testdata/sourceTarFile.tar.gz", tempFolder)
+	destDir := filepath.Join(installFolder, "org-assert.sh")
+
+	current := newUpgradeTestPackage(t, "1.0", []string{"tests.sh"})
+
+	err := asset.Upgrade(&current, installFolder)
+	assert.True(t, errors.Is(err, ErrPackageNotInstalled))
+
+	require.Nil(t, asset.Install(&current, installFolder))
+
+	t.Run("staging failure keeps installed version", func(t *testing.T) {
+		broken := newUpgradeTestPackage(t, "2.0", []string{"missing.sh"})
+
+		err := asset.Upgrade(&broken, installFolder)
+		assert.NotNil(t, err)
+
+		installed, err := NewPackageInstallerFromFileName(filepath.Join(destDir, DefaultPackageFile))
+		require.Nil(t, err)
+		assert.Equal(t, "1.0", installed.Version)
+		assert.True(t, installed.IsInstalled(destDir))
+
+		_, err = os.Stat(filepath.Join(installFolder, "bin", "tests.sh"))
+		assert.Nil(t, err)
+	})
+
+	t.Run("swap failure restores installed version", func(t *testing.T) {
+		blockedLink := filepath.Join(installFolder, "bin", "assert.sh")
+		require.Nil(t, os.MkdirAll(filepath.Join(blockedLink, "blocked"), 0755))
+		defer os.RemoveAll(blockedLink)
+
+		next := newUpgradeTestPackage(t, "2.0", []string{"tests.sh", "assert.sh"})
+
+		err := asset.Upgrade(&next, installFolder)
+		require.NotNil(t, err)
+		assert.Contains(t, err.Error(), "previous version restored")
+
+		installed, err := NewPackageInstallerFromFileName(filepath.Join(destDir, DefaultPackageFile))
+		require.Nil(t, err)
+		assert.Equal(t, "1.0", installed.Version)
+
+		target, err := os.Readlink(filepath.Join(installFolder, "bin", "tests.sh"))
+		require.Nil(t, err)
+		assert.Equal(t, filepath.Join(destDir, "tests.sh"), target)
+	})
+
+	t.Run("upgrade swaps files and links", func(t *testing.T) {
+		next := newUpgradeTestPackage(t, "2.0", []string{"assert.sh"})
+
+		require.Nil(t, asset.Upgrade(&next, installFolder))
+
+		installed, err := NewPackageInstallerFromFileName(filepath.Join(destDir, DefaultPackageFile))
+		require.Nil(t, err)
+		assert.Equal(t, "2.0", installed.Version)
+
+		_, err = os.Stat(filepath.Join(destDir, "tests.sh"))
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+
+		_, err = os.Lstat(filepath.Join(installFolder, "bin", "tests.sh"))
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+
+		_, err = os.Stat(filepath.Join(installFolder, "bin", "assert.sh"))
+		assert.Nil(t, err)
+
+		entries, err := os.ReadDir(installFolder)
+		require.Nil(t, err)
+		assert.Equal(t, 2, len(entries))
+	})
+}
+
+func TestPackageBackupRollback(t *testing.T) {
+	// the archive is extracted under t.TempDir so nothing is left in the package dir
+	releaseDir, backupDir, tempFolder := t.TempDir(), t.TempDir(), t.TempDir()
+
+	asset := NewReleaseAssets("org-assert.sh", "v1.0", "testdata/sourceTarFile.tar.gz", tempFolder)
+	installed := newUpgradeTestPackage(t, "1.0", []string{"tests.sh"})
+	require.Nil(t, asset.Install(&installed, releaseDir))
+
+	backup := new(packageBackup)
+	require.Nil(t, backup.save(&installed, filepath.Join(releaseDir, asset.name), backupDir))
+	require.Nil(t, backup.rollback(errors.New("swap failed"), backupDir))
+	assert.True(t, installed.IsInstalled(filepath.Join(releaseDir, asset.name)))
+
+	require.Nil(t, backup.save(&installed, filepath.Join(releaseDir, asset.name), backupDir))
+
+	// the release dir is gone, the backup can't be moved back
+	require.Nil(t, os.RemoveAll(releaseDir))
+
+	err := backup.rollback(errors.New("swap failed"), backupDir)
+	assert.True(t, errors.Is(err, ErrUpgradeRollbackFailed))
+	assert.Contains(t, err.Error(), backupDir)
+	assert.True(t, installed.IsInstalled(filepath.Join(backupDir, asset.name)))
+}